@@ -0,0 +1,179 @@
+package structs
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	stateNumber   = "json.Number"
+	stateBigInt   = "*big.Int"
+	stateBigFloat = "*big.Float"
+	stateBigRat   = "*big.Rat"
+)
+
+var numberRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// isBigNumber reports whether t is one of the arbitrary precision types
+// kept as exact decimal text.
+func isBigNumber(t reflect.Type) bool {
+	switch t.String() {
+	case stateNumber, stateBigInt, stateBigFloat, stateBigRat:
+		return true
+	}
+	return false
+}
+
+// numberRat returns the exact value of a number field: integers, floats,
+// json.Number and the math/big types, pointed at or not. ok is false for other
+// kinds. A json.Number that isn't a number counts as 0.
+func numberRat(field reflect.Value) (*big.Rat, bool) {
+	if !field.IsValid() {
+		return nil, false
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Rat).SetUint64(field.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if r, ok := new(big.Rat).SetString(strconv.FormatFloat(field.Float(), 'g', -1, 64)); ok {
+			return r, true
+		}
+		return new(big.Rat), true
+	case reflect.Struct:
+		if !field.CanAddr() {
+			copied := reflect.New(field.Type())
+			copied.Elem().Set(field)
+			field = copied.Elem()
+		}
+		field = field.Addr()
+	}
+	if !field.CanInterface() {
+		return nil, false
+	}
+
+	switch n := field.Interface().(type) {
+	case json.Number:
+		if r, ok := new(big.Rat).SetString(string(n)); ok {
+			return r, true
+		}
+		return new(big.Rat), true
+	case *big.Int:
+		if n != nil {
+			return new(big.Rat).SetInt(n), true
+		}
+	case *big.Float:
+		if n != nil && !n.IsInf() {
+			r, _ := n.Rat(nil)
+			return r, true
+		}
+	case *big.Rat:
+		if n != nil {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+// setBigNumber parses value into an arbitrary precision field without going
+// through float64.
+func setBigNumber(field reflect.Value, value string) error {
+	switch field.Type().String() {
+	case stateNumber:
+		if !numberRegexp.MatchString(value) {
+			return errInvalidValue
+		}
+		field.SetString(value)
+	case stateBigInt:
+		n, ok := new(big.Int).SetString(value, 10)
+		if !ok {
+			return errInvalidValue
+		}
+		field.Set(reflect.ValueOf(n))
+	case stateBigFloat:
+		// Four bits per digit keeps every decimal digit of value.
+		prec := uint(len(value) * 4)
+		if prec < 64 {
+			prec = 64
+		}
+		f, _, err := big.ParseFloat(value, 10, prec, big.ToNearestEven)
+		if err != nil {
+			return errInvalidValue
+		}
+		field.Set(reflect.ValueOf(f))
+	case stateBigRat:
+		r, ok := new(big.Rat).SetString(value)
+		if !ok {
+			return errInvalidValue
+		}
+		field.Set(reflect.ValueOf(r))
+	default:
+		return errUnsupportedType
+	}
+	return nil
+}
+
+// bigString formats an arbitrary precision value as exact decimal text.
+func bigString(v interface{}) (string, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.String(), true
+	case *big.Int:
+		if n == nil {
+			return "", false
+		}
+		return n.String(), true
+	case *big.Float:
+		if n == nil {
+			return "", false
+		}
+		return n.Text('f', -1), true
+	case *big.Rat:
+		if n == nil {
+			return "", false
+		}
+		return ratString(n), true
+	}
+	return "", false
+}
+
+// ratString returns r as a decimal when it has a finite expansion, and as
+// "a/b" otherwise.
+func ratString(r *big.Rat) string {
+	denom := new(big.Int).Set(r.Denom())
+	two, five, zero := big.NewInt(2), big.NewInt(5), new(big.Int)
+	var twos, fives int
+	for new(big.Int).Mod(denom, two).Cmp(zero) == 0 {
+		denom.Quo(denom, two)
+		twos++
+	}
+	for new(big.Int).Mod(denom, five).Cmp(zero) == 0 {
+		denom.Quo(denom, five)
+		fives++
+	}
+	if !denom.IsInt64() || denom.Int64() != 1 {
+		return r.RatString()
+	}
+	if fives > twos {
+		twos = fives
+	}
+	return r.FloatString(twos)
+}
+
+// setBigJSON fills a *big.Float or *big.Rat field from a JSON number or
+// string, which json.Unmarshal either rejects or parses at 64 bits.
+func setBigJSON(field reflect.Value, msg json.RawMessage) {
+	switch field.Type().String() {
+	case stateBigFloat, stateBigRat:
+		text := strings.Trim(string(msg), `"`)
+		if text == "null" {
+			return
+		}
+		setBigNumber(field, text)
+	}
+}
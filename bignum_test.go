@@ -0,0 +1,156 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type bigStruct struct {
+	ID     int64       `json:"id"`
+	Amount json.Number `json:"amount" required:"true"`
+	Supply *big.Int    `json:"supply" required:"true"`
+	Price  *big.Float  `json:"price"`
+	Rate   *big.Rat    `json:"rate"`
+}
+
+func ExampleToMap_bigNumbers() {
+	price, _ := new(big.Rat).SetString("19.990")
+	target := struct {
+		Amount json.Number `json:"amount"`
+		Price  *big.Rat    `json:"price"`
+	}{Amount: "12345678901234567890.12", Price: price}
+
+	fmt.Println(ToMap(target, true))
+	// Output: map[amount:12345678901234567890.12 price:19.99]
+}
+
+func TestBindRequestBigNumbers(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+	req.Form = url.Values{
+		"id":     {"9007199254740993"},
+		"amount": {"12345678901234567890.123456789"},
+		"supply": {"123456789012345678901234567890"},
+		"price":  {"0.1000000000000000000000001"},
+		"rate":   {"1.25"},
+	}
+
+	var target bigStruct
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.ID != 9007199254740993 {
+		t.Error("int64 precision lost !", target.ID)
+	}
+	result := ToMap(target, true)
+	for key, want := range map[string]string{
+		"amount": "12345678901234567890.123456789",
+		"supply": "123456789012345678901234567890",
+		"price":  "0.1000000000000000000000001",
+		"rate":   "1.25",
+	} {
+		if result[key] != want {
+			t.Error(key+" text mismatch !", result[key])
+		}
+	}
+
+	req.Form = url.Values{"amount": {"12,5"}}
+	if err := BindRequest(req, &target); err == nil || err.Error() != "amount is not a valid json.Number." {
+		t.Error("invalid number not reported !", err)
+	}
+}
+
+func TestBindRequestBigNumbersJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{
+		"amount": 0.30000000000000000001,
+		"supply": 123456789012345678901234567890,
+		"price": 19.99,
+		"rate": "1/3"
+	}`)))
+
+	var target bigStruct
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	result := ToMap(target, true)
+	if result["amount"] != "0.30000000000000000001" || result["price"] != "19.99" || result["rate"] != "1/3" {
+		t.Error("json big numbers mismatch !", result)
+	}
+	if target.Supply.String() != "123456789012345678901234567890" {
+		t.Error("json big int mismatch !", target.Supply)
+	}
+}
+
+func TestValidateAndCopyBigNumbers(t *testing.T) {
+	var target bigStruct
+	if err := ValidateStruct(&target, FailFast()); err == nil || err.Error() != "amount is required." {
+		t.Error("empty json.Number passes required !", err)
+	}
+	target.Amount = "1"
+	if err := ValidateStruct(&target); err == nil || err.Error() != "supply is required." {
+		t.Error("nil big.Int passes required !", err)
+	}
+
+	target.Supply, _ = new(big.Int).SetString("123456789012345678901234567890", 10)
+	target.Price, _, _ = big.ParseFloat("2.5", 10, 64, big.ToNearestEven)
+	target.Rate = big.NewRat(1, 3)
+	var copied bigStruct
+	if err := Copy(target, &copied); err != nil {
+		t.Fatal(err)
+	}
+	if copied.Amount != "1" || copied.Supply.Cmp(target.Supply) != 0 || copied.Price.Cmp(target.Price) != 0 || copied.Rate.Cmp(target.Rate) != 0 {
+		t.Error("copy big numbers mismatch !", copied)
+	}
+}
+
+func TestValidateBigNumbers(t *testing.T) {
+	type numbers struct {
+		N     json.Number `json:"n" validate:"min=5,max=100"`
+		Int   *big.Int    `json:"int" validate:"max=18446744073709551616"`
+		Float *big.Float  `json:"float" validate:"min=0.1"`
+		Rat   *big.Rat    `json:"rat" validate:"max=1/3"`
+		Price float64     `json:"price" validate:"min=0.1"`
+		Total json.Number `json:"total" validate:"gtfield=price"`
+	}
+
+	target := numbers{
+		N:     "10",
+		Int:   new(big.Int).Lsh(big.NewInt(1), 64),
+		Float: big.NewFloat(0.5),
+		Rat:   big.NewRat(1, 3),
+		Price: 0.1,
+		Total: "0.11",
+	}
+	if err := ValidateStruct(&target); err != nil {
+		t.Error("valid big numbers rejected !", err)
+	}
+
+	target.N = "4.99"
+	target.Int.Add(target.Int, big.NewInt(1))
+	target.Float = big.NewFloat(0.05)
+	target.Rat = big.NewRat(1, 2)
+	target.Total = "0.1"
+	expected := "n must be at least 5. int must be at most 18446744073709551616. float must be at least 0.1. " +
+		"rat must be at most 1/3. total must be greater than price."
+	err := ValidateStruct(&target)
+	if err == nil || err.Error() != expected {
+		t.Error("big number bounds mismatch !", err)
+	}
+	if errs := err.(ValidationErrors); errs[1].Translate(Locale("id")) != "int maksimal 18446744073709551616." {
+		t.Error("big number message mismatch !", errs[1].Translate(Locale("id")))
+	}
+
+	withLen := struct {
+		N json.Number `validate:"len=2"`
+	}{N: "10"}
+	if err := ValidateStruct(&withLen); err == nil || err.Error() != "len rule of N applies to strings, slices and maps only." {
+		t.Error("len on json.Number not rejected !", err)
+	}
+}
@@ -0,0 +1,57 @@
+package structs
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodySize is the largest decoded request body BindRequest reads
+// unless MaxBodySize says otherwise.
+const DefaultMaxBodySize = 10 << 20
+
+// readBody reads the request body, decoding gzip and deflate according to
+// Content-Encoding. The limit applies to the decoded size, so a small
+// compressed body can't expand past it.
+func readBody(request *http.Request, limit int64) ([]byte, error) {
+	if request.Body == nil {
+		return nil, nil
+	}
+
+	var reader io.Reader = request.Body
+	switch encoding := strings.ToLower(strings.TrimSpace(request.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	case "deflate":
+		zr, err := zlib.NewReader(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		reader = zr
+	default:
+		return nil, errors.New(encoding + " content encoding is not supported.")
+	}
+
+	if limit <= 0 {
+		return ioutil.ReadAll(reader)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, errors.New("Request body is too large.")
+	}
+	return body, nil
+}
@@ -0,0 +1,76 @@
+package structs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func compressedRequest(encoding string, body []byte) *http.Request {
+	var buf bytes.Buffer
+	switch encoding {
+	case "gzip":
+		w := gzip.NewWriter(&buf)
+		w.Write(body)
+		w.Close()
+	case "deflate":
+		w := zlib.NewWriter(&buf)
+		w.Write(body)
+		w.Close()
+	default:
+		buf.Write(body)
+	}
+
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", encoding)
+	req.Body = ioutil.NopCloser(&buf)
+	return req
+}
+
+func TestBindRequestCompressedBody(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate", "identity"} {
+		var target bindRequestStruct
+		req := compressedRequest(encoding, []byte(`{"t_string": "winterfell", "t_int": 7}`))
+		if err := BindRequest(req, &target); err != nil {
+			t.Fatal(encoding, err)
+		}
+		if target.TString != "winterfell" || target.TInt != 7 {
+			t.Error(encoding+" body mismatch !", target)
+		}
+	}
+
+	var target bindRequestStruct
+	req := compressedRequest("br", []byte(`{}`))
+	if err := BindRequest(req, &target); err == nil || err.Error() != "br content encoding is not supported." {
+		t.Error("unsupported encoding not reported !", err)
+	}
+
+	req = compressedRequest("gzip", []byte(`not gzip`))
+	req.Body = ioutil.NopCloser(strings.NewReader("not gzip"))
+	if err := BindRequest(req, &target); err == nil {
+		t.Error("corrupt gzip body not reported !")
+	}
+}
+
+func TestBindRequestMaxBodySize(t *testing.T) {
+	// 64KiB of zeros compress to a few hundred bytes but decode past the limit.
+	body := append([]byte(`{"t_string": "`), bytes.Repeat([]byte("0"), 1<<16)...)
+	body = append(body, []byte(`"}`)...)
+
+	var target bindRequestStruct
+	req := compressedRequest("gzip", body)
+	err := BindRequest(req, &target, MaxBodySize(1<<10))
+	if err == nil || err.Error() != "Request body is too large." {
+		t.Error("decompressed size not limited !", err)
+	}
+
+	req = compressedRequest("gzip", body)
+	if err := BindRequest(req, &target, MaxBodySize(0)); err != nil || len(target.TString) != 1<<16 {
+		t.Error("unlimited body mismatch !", err)
+	}
+}
@@ -0,0 +1,155 @@
+package structs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// RuleCtx checks a field with access to ctx, e.g. looking it up in a
+// database. A returned error fails the field with its text as message,
+// unless ctx is done, in which case validation stops with ctx.Err().
+type RuleCtx func(ctx context.Context, fl FieldContext) error
+
+// ctxRules holds rules registered with RegisterRuleCtx, guarded by rulesMu.
+var ctxRules = map[string]RuleCtx{}
+
+// RegisterRuleCtx makes fn available to `validate` tag under name, replacing
+// any rule already registered with that name. Like RegisterRule, it panics
+// for `required`, `required_*` and `dive`.
+func RegisterRuleCtx(name string, fn RuleCtx) {
+	checkRuleName(name)
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	delete(rules, name)
+	ctxRules[name] = fn
+}
+
+// ValidateStructCtx works like ValidateStruct, passing ctx to the rules
+// registered with RegisterRuleCtx. Those run once the field passes its other
+// rules and the rest of the struct is checked, so with FailFast they are
+// skipped if anything else fails. Concurrency runs them for several fields at
+// once. Failures are reported in field order either way.
+func ValidateStructCtx(ctx context.Context, target interface{}, opts ...ValidateOption) error {
+	val := reflect.ValueOf(target)
+
+	if val.Kind() != reflect.Ptr {
+		return errors.New("Target can't be value")
+	}
+	val = val.Elem()
+
+	v := &validator{options: newValidateOptions(opts)}
+	if err := v.validateStruct(val, ""); err != nil {
+		return err
+	}
+	if err := v.runJobs(ctx); err != nil {
+		return err
+	}
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+type ctxCall struct {
+	ruleCall
+	fn RuleCtx
+}
+
+// ctxJob holds the context rules of one field, to be run once the walk over
+// the struct is done. pos is where its failure goes in the errors.
+type ctxJob struct {
+	pos   int
+	fl    FieldContext
+	field reflect.Value
+	calls []ctxCall
+	msg   string
+
+	fail *FieldError
+	err  error
+}
+
+// run checks the field against its rules, stopping at the first one it
+// fails.
+func (j *ctxJob) run(ctx context.Context) {
+	for _, c := range j.calls {
+		if j.err = ctx.Err(); j.err != nil {
+			return
+		}
+
+		fl := j.fl
+		fl.Param = c.param
+		err := c.fn(ctx, fl)
+		if err == nil {
+			continue
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			j.err = ctxErr
+			return
+		}
+
+		j.fail = &FieldError{Path: fl.Path, Rule: c.name, Param: c.param, msg: j.msg, message: err.Error(), raw: true}
+		if j.field.CanInterface() {
+			j.fail.Value = j.field.Interface()
+		}
+		if j.msg != "" {
+			j.fail.message = defaultMessage(j.fail)
+		}
+		return
+	}
+}
+
+// runJobs runs the queued context rules, up to the Concurrency option at
+// once, and merges their failures into the errors in field order.
+func (v *validator) runJobs(ctx context.Context) error {
+	if len(v.jobs) == 0 || v.done() {
+		return nil
+	}
+
+	workers := v.options.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, job := range v.jobs {
+		if workers == 1 {
+			job.run(ctx)
+			if job.err != nil || (job.fail != nil && v.options.failFast) {
+				break
+			}
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(job *ctxJob) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			job.run(ctx)
+		}(job)
+	}
+	wg.Wait()
+
+	errs := make(ValidationErrors, 0, len(v.errs))
+	next := 0
+	for _, job := range v.jobs {
+		if job.err != nil {
+			return job.err
+		}
+		if job.fail == nil {
+			continue
+		}
+		errs = append(errs, v.errs[next:job.pos]...)
+		errs, next = append(errs, job.fail), job.pos
+		if v.options.failFast {
+			v.errs = errs
+			return nil
+		}
+	}
+	v.errs = append(errs, v.errs[next:]...)
+	return nil
+}
@@ -0,0 +1,118 @@
+package structs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func ExampleValidateStructCtx() {
+	registered := map[string]bool{"ned@example.com": true}
+	RegisterRuleCtx("unique_email", func(ctx context.Context, fl FieldContext) error {
+		if registered[fl.Value.String()] {
+			return errors.New(fl.Path + " is already registered.")
+		}
+		return nil
+	})
+
+	signup := struct {
+		Email string `json:"email" validate:"required,unique_email"`
+	}{Email: "ned@example.com"}
+
+	fmt.Println(ValidateStructCtx(context.Background(), &signup))
+	// Output: email is already registered.
+}
+
+type ctxItem struct {
+	Name       string `json:"name" validate:"min=2"`
+	CategoryID int    `json:"category_id" validate:"category_exists"`
+}
+
+func TestValidateStructCtxConcurrency(t *testing.T) {
+	var running, peak int32
+	RegisterRuleCtx("category_exists", func(ctx context.Context, fl FieldContext) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		if fl.Value.Int()%2 == 0 {
+			return fmt.Errorf("%s %d doesn't exist.", fl.Path, fl.Value.Int())
+		}
+		return nil
+	})
+
+	target := struct {
+		Items []ctxItem `json:"items"`
+	}{}
+	for i := 1; i <= 8; i++ {
+		target.Items = append(target.Items, ctxItem{Name: "item", CategoryID: i})
+	}
+	target.Items[3].Name = "x"
+
+	expected := "items[1].category_id 2 doesn't exist. items[3].name must have a length of at least 2. " +
+		"items[3].category_id 4 doesn't exist. " +
+		"items[5].category_id 6 doesn't exist. items[7].category_id 8 doesn't exist."
+	for _, workers := range []int{1, 3} {
+		atomic.StoreInt32(&peak, 0)
+		err := ValidateStructCtx(context.Background(), &target, Concurrency(workers))
+		if err == nil || err.Error() != expected {
+			t.Error("context rule errors mismatch !", workers, err)
+		}
+		if peak > int32(workers) {
+			t.Error("concurrency limit exceeded !", workers, peak)
+		}
+	}
+	if peak < 2 {
+		t.Error("context rules did not run concurrently !", peak)
+	}
+
+	err := ValidateStructCtx(context.Background(), &target, Concurrency(3), FailFast())
+	if err == nil || err.Error() != "items[3].name must have a length of at least 2." {
+		t.Error("FailFast context rules mismatch !", err)
+	}
+	target.Items[3].Name = "item"
+	err = ValidateStructCtx(context.Background(), &target, Concurrency(3), FailFast())
+	if err == nil || err.Error() != "items[1].category_id 2 doesn't exist." {
+		t.Error("FailFast context rules mismatch !", err)
+	}
+}
+
+func TestValidateStructCtxCanceled(t *testing.T) {
+	var once sync.Once
+	started := make(chan struct{})
+	RegisterRuleCtx("slow_lookup", func(ctx context.Context, fl FieldContext) error {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	target := struct {
+		A string `validate:"slow_lookup"`
+		B string `validate:"slow_lookup"`
+	}{A: "a", B: "b"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	if err := ValidateStructCtx(ctx, &target); err != context.Canceled {
+		t.Error("canceled validation mismatch !", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := ValidateStructCtx(ctx, &target, Concurrency(2)); err != context.DeadlineExceeded {
+		t.Error("deadline mismatch !", err)
+	}
+}
@@ -0,0 +1,206 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// condition reports whether a field is required, judging by the other fields
+// of the structs holding it.
+type condition func(param string, parents []reflect.Value) (bool, error)
+
+var conditions = map[string]condition{
+	"required_if":      requiredIf,
+	"required_unless":  requiredUnless,
+	"required_with":    requiredWith,
+	"required_without": requiredWithout,
+}
+
+// lookupField finds the field at path, e.g. "Password" or "Address.Country",
+// starting from the innermost struct in parents. Every leading "../" starts
+// one struct further out. Segments match `json` tags, then field names.
+// The returned value is invalid when a nil pointer is in the way.
+func lookupField(parents []reflect.Value, path string) (reflect.Value, error) {
+	up := 0
+	for strings.HasPrefix(path, "../") {
+		path, up = path[3:], up+1
+	}
+	if up >= len(parents) {
+		return reflect.Value{}, errors.New("can't find field " + path + ".")
+	}
+
+	value := parents[len(parents)-1-up]
+	for _, name := range strings.Split(path, ".") {
+		value, _ = resolve(value)
+		if !value.IsValid() {
+			return value, nil
+		}
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, errors.New("can't find field " + path + ".")
+		}
+
+		i := fieldIndex(value.Type(), name)
+		if i < 0 {
+			return reflect.Value{}, errors.New("can't find field " + path + ".")
+		}
+		value = value.Field(i)
+	}
+	return value, nil
+}
+
+// fieldIndex returns the index of the exported field of t named name by its
+// JSON key or, failing that, its field name, or -1.
+func fieldIndex(t reflect.Type, name string) int {
+	index := -1
+	for i := 0; i < t.NumField(); i++ {
+		typeField := t.Field(i)
+		if typeField.PkgPath != "" {
+			continue
+		}
+		if key, ok := jsonKey(typeField); ok && key == name {
+			return i
+		}
+		if index < 0 && typeField.Name == name {
+			index = i
+		}
+	}
+	return index
+}
+
+// lookupValue finds the field at path and resolves it like the field being
+// validated.
+func lookupValue(parents []reflect.Value, path string) (reflect.Value, bool, error) {
+	other, err := lookupField(parents, path)
+	if err != nil || !other.IsValid() {
+		return reflect.Value{}, true, err
+	}
+	value, missing := resolve(other)
+	return value, missing, nil
+}
+
+func ruleEqField(fl FieldContext) (bool, error) {
+	other, _, err := lookupValue(fl.parents, fl.Param)
+	if err != nil {
+		return false, err
+	}
+	return other.IsValid() && reflect.DeepEqual(fl.Value.Interface(), other.Interface()), nil
+}
+
+func ruleNeField(fl FieldContext) (bool, error) {
+	equal, err := ruleEqField(fl)
+	return !equal, err
+}
+
+func ruleGtField(fl FieldContext) (bool, error) {
+	n, err := compareField(fl.Value, fl.Param, fl.parents)
+	return n > 0, err
+}
+
+func ruleLtField(fl FieldContext) (bool, error) {
+	n, err := compareField(fl.Value, fl.Param, fl.parents)
+	return n < 0, err
+}
+
+// compareField compares field with the field at path, returning -1, 0 or 1.
+// Times are compared by instant, numbers, json.Number and math/big ones
+// included, by exact value and strings, slices and maps by length. A missing
+// field at path compares as equal.
+func compareField(field reflect.Value, path string, parents []reflect.Value) (int, error) {
+	other, missing, err := lookupValue(parents, path)
+	if err != nil || missing {
+		return 0, err
+	}
+
+	if a, ok := field.Interface().(time.Time); ok {
+		b, ok := other.Interface().(time.Time)
+		if !ok {
+			return 0, errors.New("can't compare time.Time with " + other.Type().String() + ".")
+		}
+		return a.Compare(b), nil
+	}
+
+	a, aNumber := numberRat(field)
+	b, bNumber := numberRat(other)
+	if aNumber && bNumber {
+		return a.Cmp(b), nil
+	}
+	if aNumber || bNumber {
+		return 0, errors.New("can't compare " + field.Type().String() + " with " + other.Type().String() + ".")
+	}
+
+	x, err := length(field)
+	if err != nil {
+		return 0, err
+	}
+	y, err := length(other)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// requiredIf requires the field when every "Field value" pair in param
+// matches, e.g. `required_if=Country DE`.
+func requiredIf(param string, parents []reflect.Value) (bool, error) {
+	pairs := strings.Fields(param)
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return false, errors.New("needs field and value pairs, got " + param + ".")
+	}
+
+	for i := 0; i < len(pairs); i += 2 {
+		other, _, err := lookupValue(parents, pairs[i])
+		if err != nil {
+			return false, err
+		}
+		if !other.IsValid() || fmt.Sprint(other.Interface()) != pairs[i+1] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// requiredUnless requires the field unless every "Field value" pair in param
+// matches.
+func requiredUnless(param string, parents []reflect.Value) (bool, error) {
+	matched, err := requiredIf(param, parents)
+	return !matched, err
+}
+
+// requiredWith requires the field when any of the space separated fields in
+// param is present.
+func requiredWith(param string, parents []reflect.Value) (bool, error) {
+	for _, path := range strings.Fields(param) {
+		_, missing, err := lookupValue(parents, path)
+		if err != nil {
+			return false, err
+		}
+		if !missing {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// requiredWithout requires the field when any of the space separated fields
+// in param is missing.
+func requiredWithout(param string, parents []reflect.Value) (bool, error) {
+	for _, path := range strings.Fields(param) {
+		_, missing, err := lookupValue(parents, path)
+		if err != nil {
+			return false, err
+		}
+		if missing {
+			return true, nil
+		}
+	}
+	return false, nil
+}
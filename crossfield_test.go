@@ -0,0 +1,119 @@
+package structs
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type crossAddress struct {
+	Country string `json:"country"`
+	VatID   string `json:"vat_id" validate:"required_if=country DE"`
+	Zip     string `json:"zip" validate:"required_unless=../guest true"`
+}
+
+type crossForm struct {
+	Password        string       `json:"password"`
+	PasswordConfirm string       `json:"password_confirm" validate:"eqfield=password"`
+	OldPassword     string       `json:"old_password" validate:"nefield=password"`
+	StartDate       time.Time    `json:"start_date"`
+	EndDate         time.Time    `json:"end_date" validate:"gtfield=start_date"`
+	Min             int          `json:"min"`
+	Max             int          `json:"max" validate:"gtfield=min"`
+	Guest           bool         `json:"guest"`
+	Phone           string       `json:"phone" validate:"required_without=email"`
+	Email           string       `json:"email"`
+	Nickname        string       `json:"nickname" validate:"required_with=address.country"`
+	Address         crossAddress `json:"address"`
+}
+
+func ExampleValidateStruct_crossField() {
+	form := struct {
+		Password        string `json:"password"`
+		PasswordConfirm string `json:"password_confirm" validate:"eqfield=password"`
+	}{Password: "secret", PasswordConfirm: "secrte"}
+
+	fmt.Println(ValidateStruct(&form))
+	// Output: password_confirm must be equal to password.
+}
+
+func TestValidateStructCrossField(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	form := crossForm{
+		Password:        "secret",
+		PasswordConfirm: "secret",
+		OldPassword:     "public",
+		StartDate:       start,
+		EndDate:         start.Add(time.Hour),
+		Min:             1,
+		Max:             2,
+		Guest:           true,
+		Email:           "a@b.c",
+		Address:         crossAddress{Country: "FR"},
+	}
+	if err := ValidateStruct(&form); err == nil || err.Error() != "nickname is required." {
+		t.Error("required_with mismatch !", err)
+	}
+
+	form.Nickname = "arya"
+	if err := ValidateStruct(&form); err != nil {
+		t.Error("valid cross field form rejected !", err)
+	}
+
+	form.PasswordConfirm = "other"
+	form.OldPassword = "secret"
+	form.EndDate = start
+	form.Max = 0
+	form.Email = ""
+	form.Guest = false
+	form.Address.Country = "DE"
+	err := ValidateStruct(&form)
+	expected := "password_confirm must be equal to password. old_password must not be equal to password. " +
+		"end_date must be greater than start_date. max must be greater than min. phone is required. " +
+		"address.vat_id is required. address.zip is required."
+	if err == nil || err.Error() != expected {
+		t.Error("cross field errors mismatch !", err)
+	}
+	if errs, ok := err.(ValidationErrors); !ok || errs[5].Rule != "required_if" || errs[5].Param != "country DE" {
+		t.Error("cross field rule mismatch !", err)
+	}
+}
+
+func TestValidateStructCrossFieldMisconfigured(t *testing.T) {
+	unknown := struct {
+		A string `validate:"eqfield=B"`
+	}{}
+	if err := ValidateStruct(&unknown); err == nil || err.Error() != "eqfield rule of A can't find field B." {
+		t.Error("unknown field error mismatch !", err)
+	}
+
+	pairs := struct {
+		A string
+		B string `validate:"required_if=A"`
+	}{}
+	if err := ValidateStruct(&pairs); err == nil || err.Error() != "required_if rule of B needs field and value pairs, got A." {
+		t.Error("required_if pairs error mismatch !", err)
+	}
+
+	up := struct {
+		A string `validate:"ltfield=../B"`
+	}{A: "x"}
+	if err := ValidateStruct(&up); err == nil || err.Error() != "ltfield rule of A can't find field B." {
+		t.Error("parent field error mismatch !", err)
+	}
+}
+
+func TestValidateStructCrossFieldTagOptions(t *testing.T) {
+	form := struct {
+		Name    string `json:"name,omitempty"`
+		Confirm string `json:"confirm" validate:"eqfield=name"`
+		Email   string `json:"email" validate:"required_with=name"`
+		Max     int    `json:"-"`
+		Count   int    `json:"count" validate:"ltfield=Max"`
+	}{Name: "arya", Confirm: "arry", Count: 3, Max: 5}
+
+	err := ValidateStruct(&form)
+	if err == nil || err.Error() != "confirm must be equal to name. email is required." {
+		t.Error("json tag options lookup mismatch !", err)
+	}
+}
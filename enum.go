@@ -0,0 +1,160 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	enumsMu sync.RWMutex
+	enums   = map[reflect.Type]*enumSet{}
+)
+
+// enumSet maps the names of an enum to its integer constants and back.
+type enumSet struct {
+	values map[string]int64
+	names  map[int64]string
+}
+
+// RegisterEnum makes every field of type t bind from and convert to the names
+// in values, as if it carried an `enum` tag. t must be an integer type.
+func RegisterEnum(t reflect.Type, values map[string]int64) {
+	enum := &enumSet{values: values, names: make(map[int64]string, len(values))}
+	for name, n := range values {
+		enum.names[n] = name
+	}
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+	enums[t] = enum
+}
+
+// enumOf returns the enum of a field from its `enum` tag, e.g.
+// `enum:"active=1,disabled=2"`, or from the registry. Slice fields use the
+// enum of their element type.
+func enumOf(typeField reflect.StructField) (*enumSet, error) {
+	if tag, ok := typeField.Tag.Lookup("enum"); ok {
+		return parseEnum(typeField.Name, tag)
+	}
+
+	t := typeField.Type
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+	return enums[t], nil
+}
+
+func parseEnum(name, tag string) (*enumSet, error) {
+	enum := &enumSet{values: map[string]int64{}, names: map[int64]string{}}
+	for _, pair := range strings.Split(tag, ",") {
+		i := strings.Index(pair, "=")
+		if i < 0 {
+			return nil, errors.New(name + " enum tag needs name=value pairs.")
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(pair[i+1:]), 10, 64)
+		if err != nil {
+			return nil, errors.New(name + " enum tag needs name=value pairs.")
+		}
+		enum.values[strings.TrimSpace(pair[:i])] = n
+		enum.names[n] = strings.TrimSpace(pair[:i])
+	}
+	return enum, nil
+}
+
+// set stores the constant named value into field.
+func (e *enumSet) set(field reflect.Value, value string) error {
+	if value == "" {
+		return nil
+	}
+	n, ok := e.values[value]
+	if !ok {
+		return errInvalidValue
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(n)
+	default:
+		return errUnsupportedType
+	}
+	return nil
+}
+
+// setNames stores the constants named by names into field, a slice, naming
+// the failing element after key in errors.
+func (e *enumSet) setNames(field reflect.Value, names []string, key, tagName string) error {
+	s := reflect.MakeSlice(field.Type(), len(names), len(names))
+	for i, name := range names {
+		if err := e.set(s.Index(i), name); err != nil {
+			return fieldError(err, fmt.Sprintf("%s[%d]", key, i), s.Type().Elem().String(), tagName)
+		}
+	}
+	field.Set(s)
+	return nil
+}
+
+// setJSON stores the names held by msg, a JSON string or array of strings,
+// into field. Numbers were decoded by encoding/json already.
+func (e *enumSet) setJSON(field reflect.Value, msg json.RawMessage, key string) error {
+	var name string
+	if json.Unmarshal(msg, &name) == nil {
+		if err := e.set(field, name); err != nil {
+			return fieldError(err, key, field.Type().String(), "json")
+		}
+		return nil
+	}
+
+	var names []string
+	if field.Kind() != reflect.Slice || json.Unmarshal(msg, &names) != nil {
+		return nil
+	}
+	return e.setNames(field, names, key, "json")
+}
+
+// name returns the name of the constant held by field.
+func (e *enumSet) name(field reflect.Value) (string, bool) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		name, ok := e.names[field.Int()]
+		return name, ok
+	}
+	return "", false
+}
+
+// sliceNames returns the names of the constants held by field, a slice.
+func (e *enumSet) sliceNames(field reflect.Value) ([]string, bool) {
+	if field.Kind() != reflect.Slice || field.IsNil() {
+		return nil, false
+	}
+	names := make([]string, field.Len())
+	for i := range names {
+		name, ok := e.name(field.Index(i))
+		if !ok {
+			return nil, false
+		}
+		names[i] = name
+	}
+	return names, true
+}
+
+// list returns the names of the enum ordered by value.
+func (e *enumSet) list() string {
+	values := make([]int64, 0, len(e.names))
+	for n := range e.names {
+		values = append(values, n)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	names := make([]string, len(values))
+	for i, n := range values {
+		names[i] = e.names[n]
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,184 @@
+package structs
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type enumStatus int
+
+const (
+	enumStatusActive   enumStatus = 1
+	enumStatusDisabled enumStatus = 2
+)
+
+type enumRole int8
+
+func init() {
+	RegisterEnum(reflect.TypeOf(enumRole(0)), map[string]int64{"admin": 1, "member": 2})
+}
+
+type enumStruct struct {
+	Status enumStatus `json:"status" enum:"active=1,disabled=2"`
+	Role   enumRole   `json:"role"`
+	Roles  []enumRole `json:"roles" split:","`
+}
+
+func ExampleRegisterEnum() {
+	type Status int
+	RegisterEnum(reflect.TypeOf(Status(0)), map[string]int64{"active": 1, "disabled": 2})
+
+	req, _ := http.NewRequest("GET", "http://localhost:9000?status=disabled", nil)
+	var target struct {
+		Status Status `json:"status"`
+	}
+	err := BindRequest(req, &target)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(target.Status, ToMap(target)["status"])
+	// Output: 2 disabled
+}
+
+func TestBindRequestEnum(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+	req.Form = url.Values{"status": {"active"}, "role": {"member"}, "roles": {"admin,member"}}
+
+	var target enumStruct
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Status != enumStatusActive || target.Role != 2 {
+		t.Error("enum binding mismatch !", target)
+	}
+	if !reflect.DeepEqual(target.Roles, []enumRole{1, 2}) {
+		t.Error("split enum binding mismatch !", target.Roles)
+	}
+
+	req.Form = url.Values{"status": {"deleted"}}
+	err := BindRequest(req, &target)
+	if err == nil || err.Error() != "status is not a valid structs.enumStatus." {
+		t.Error("unknown enum name not reported !", err)
+	}
+}
+
+func TestToMapEnum(t *testing.T) {
+	target := enumStruct{Status: enumStatusDisabled, Role: 1}
+	result := ToMap(target)
+	if result["status"] != "disabled" || result["role"] != "admin" {
+		t.Error("enum names mismatch !", result)
+	}
+
+	var back enumStruct
+	if err := FromMap(result, &back); err != nil || back.Status != enumStatusDisabled || back.Role != 1 {
+		t.Error("enum round trip mismatch !", err, back)
+	}
+}
+
+func TestValidateStructEnum(t *testing.T) {
+	target := enumStruct{Status: 7}
+	err := ValidateStruct(&target)
+	if err == nil || err.Error() != "status must be one of active, disabled." {
+		t.Error("enum outside set not rejected !", err)
+	}
+
+	target.Status = 0
+	if err := ValidateStruct(&target); err != nil {
+		t.Error("unset enum rejected !", err)
+	}
+}
+
+func TestEnumSlices(t *testing.T) {
+	target := enumStruct{Status: enumStatusActive, Role: 1, Roles: []enumRole{1, 2}}
+	if err := ValidateStruct(&target); err != nil {
+		t.Error("valid enum slice rejected !", err)
+	}
+
+	target.Roles = []enumRole{1, 7, 2, 9}
+	err := ValidateStruct(&target)
+	if err == nil || err.Error() != "roles[1] must be one of admin, member. roles[3] must be one of admin, member." {
+		t.Error("enum slice elements mismatch !", err)
+	}
+
+	target.Roles = []enumRole{2, 1}
+	m := ToMap(&target)
+	if !reflect.DeepEqual(m["roles"], []string{"member", "admin"}) {
+		t.Error("enum slice not converted to names !", m["roles"])
+	}
+
+	var back enumStruct
+	if err := FromMap(m, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, target) {
+		t.Error("enum slice round trip mismatch !", back)
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+	bind := func(body string) (enumStruct, error) {
+		req, _ := http.NewRequest("POST", "", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		var target enumStruct
+		err := BindRequest(req, &target)
+		return target, err
+	}
+
+	target, err := bind(`{"status": "disabled", "role": "member", "roles": ["admin", "member"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Status != enumStatusDisabled || target.Role != 2 || !reflect.DeepEqual(target.Roles, []enumRole{1, 2}) {
+		t.Error("json enum names not bound !", target)
+	}
+
+	if target, err = bind(`{"status": 1, "roles": [2]}`); err != nil || target.Status != enumStatusActive || target.Roles[0] != 2 {
+		t.Error("json enum numbers not bound !", target, err)
+	}
+
+	if _, err := bind(`{"status": "paused"}`); err == nil || err.Error() != "status is not a valid structs.enumStatus." {
+		t.Error("unknown json enum name mismatch !", err)
+	}
+	if _, err := bind(`{"roles": ["admin", "king"]}`); err == nil || err.Error() != "roles[1] is not a valid structs.enumRole." {
+		t.Error("unknown json enum slice name mismatch !", err)
+	}
+}
+
+func TestEnumWrapped(t *testing.T) {
+	type wrapped struct {
+		Status   Optional[enumStatus] `json:"status" enum:"active=1,disabled=2"`
+		Previous *enumStatus          `json:"previous" enum:"active=1,disabled=2"`
+	}
+
+	req, _ := http.NewRequest("GET", "", nil)
+	req.Form = url.Values{"status": {"active"}}
+	var target wrapped
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	disabled := enumStatusDisabled
+	target.Previous = &disabled
+	if err := ValidateStruct(&target); err != nil {
+		t.Error("valid wrapped enum rejected !", err)
+	}
+
+	m := ToMap(target)
+	if m["status"] != "active" || m["previous"] != "disabled" {
+		t.Error("wrapped enum not converted to names !", m)
+	}
+
+	unknown := enumStatus(7)
+	target.Status, target.Previous = Some(unknown), &unknown
+	err := ValidateStruct(&target)
+	if err == nil || err.Error() != "status must be one of active, disabled. previous must be one of active, disabled." {
+		t.Error("wrapped enum outside set not rejected !", err)
+	}
+
+	if err := ValidateStruct(&wrapped{}); err != nil {
+		t.Error("absent wrapped enum rejected !", err)
+	}
+}
@@ -0,0 +1,72 @@
+package structs
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// BindEnv will scan your struct and bind environment variables into your
+// struct according to `env` tag on struct, using the same conversions and
+// `default` tag as BindRequest.
+// Nested structs are bound with their `env` tag (or upper cased field name)
+// appended to the prefix, e.g. prefix "APP" and tags `env:"DB"` / `env:"HOST"`
+// reads APP_DB_HOST.
+func BindEnv(target interface{}, prefix string) error {
+	val := reflect.ValueOf(target)
+
+	if val.Kind() != reflect.Ptr {
+		return errors.New("Target can't be value")
+	}
+	return bindEnv(val.Elem(), strings.TrimSuffix(prefix, "_"))
+}
+
+func bindEnv(val reflect.Value, prefix string) error {
+	for i := 0; i < val.NumField(); i++ {
+		typeField := val.Type().Field(i)
+		tag := typeField.Tag.Get("env")
+		if tag == "-" || typeField.PkgPath != "" {
+			continue
+		}
+
+		if typeField.Type.Kind() == reflect.Struct {
+			if tag == "" {
+				tag = strings.ToUpper(typeField.Name)
+			}
+			if err := bindEnv(val.Field(i), envName(prefix, tag)); err != nil {
+				return err
+			}
+			continue
+		}
+		if tag == "" {
+			continue
+		}
+
+		key := envName(prefix, tag)
+		value, found := os.LookupEnv(key)
+		if found {
+			modified, err := modify(typeField, value)
+			if err != nil {
+				return err
+			}
+			value = modified
+		} else if def, ok := typeField.Tag.Lookup("default"); ok {
+			value = def
+		} else {
+			continue
+		}
+
+		if err := bindValue(val.Field(i), typeField, key, value, "env"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func envName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
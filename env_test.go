@@ -0,0 +1,82 @@
+package structs
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+type envConfig struct {
+	Name    string  `env:"NAME" required:"true"`
+	Port    int     `env:"PORT" default:"8080"`
+	Debug   bool    `env:"DEBUG"`
+	Ratio   float64 `env:"RATIO"`
+	Skipped string  `env:"-"`
+	DB      struct {
+		Host string `env:"HOST" default:"localhost"`
+		Port int32  `env:"PORT"`
+	} `env:"DB"`
+}
+
+func ExampleBindEnv() {
+	os.Setenv("APP_NAME", "structs")
+	defer os.Unsetenv("APP_NAME")
+
+	var cfg struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT" default:"8080"`
+	}
+	err := BindEnv(&cfg, "APP")
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(cfg.Name, cfg.Port)
+	// Output: structs 8080
+}
+
+func TestBindEnv(t *testing.T) {
+	t.Setenv("APP_NAME", " structs ")
+	t.Setenv("APP_DEBUG", "1")
+	t.Setenv("APP_RATIO", "0.25")
+	t.Setenv("APP_DB_PORT", "5432")
+
+	var cfg envConfig
+	if err := BindEnv(&cfg, "APP_"); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "structs" {
+		t.Error("env string mismatch !")
+	}
+	if cfg.Port != 8080 {
+		t.Error("env default mismatch !")
+	}
+	if cfg.Debug != true {
+		t.Error("env bool mismatch !")
+	}
+	if cfg.Ratio != 0.25 {
+		t.Error("env float mismatch !")
+	}
+	if cfg.DB.Host != "localhost" {
+		t.Error("env nested default mismatch !")
+	}
+	if cfg.DB.Port != 5432 {
+		t.Error("env nested prefix mismatch !")
+	}
+}
+
+func TestBindEnvInvalid(t *testing.T) {
+	t.Setenv("PORT", "eighty")
+
+	var cfg struct {
+		Port int `env:"PORT"`
+	}
+	err := BindEnv(&cfg, "")
+	if err == nil || err.Error() != "PORT is not a valid int." {
+		t.Error("invalid env value not reported !", err)
+	}
+
+	if err := BindEnv(cfg, ""); err == nil {
+		t.Error("value target not rejected !")
+	}
+}
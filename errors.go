@@ -0,0 +1,39 @@
+package structs
+
+import "strings"
+
+// FieldError describes a field failing one validation rule. FieldError built
+// outside this package, e.g. by Validate, get a message from their rule.
+type FieldError struct {
+	Path  string
+	Rule  string
+	Param string
+	Value interface{}
+
+	message string
+	msg     string // `msg` tag of the field
+	raw     bool   // message is the text of an error
+}
+
+func (e *FieldError) Error() string {
+	if e.message == "" {
+		return defaultMessage(e)
+	}
+	return e.message
+}
+
+// defaultMessage renders the "en" template of e.
+func defaultMessage(e *FieldError) string {
+	return Locale("en").Translate(e)
+}
+
+// ValidationErrors lists every field failing validation, in field order.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Error()
+	}
+	return strings.Join(messages, " ")
+}
@@ -0,0 +1,46 @@
+package structs
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleValidationErrors() {
+	target := struct {
+		Name  string `json:"name" required:"true"`
+		Email string `json:"email" validate:"required"`
+		Age   int    `json:"age" validate:"min=18"`
+	}{Age: 12}
+
+	err := ValidateStruct(&target)
+	for _, fe := range err.(ValidationErrors) {
+		fmt.Printf("%s %s=%s %#v\n", fe.Path, fe.Rule, fe.Param, fe.Value)
+	}
+	fmt.Println(err)
+	// Output: name required= ""
+	// email required= ""
+	// age min=18 12
+	// name is required. email is required. age must be at least 18.
+}
+
+func TestValidationErrors(t *testing.T) {
+	var target bindRequestStruct
+	err := ValidateStruct(&target)
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 10 {
+		t.Fatal("every failing field not listed !", err)
+	}
+	if errs[0].Path != "t_int" || errs[7].Path != "t_bool" || errs[9].Path != "t_unsupported" || errs[9].Rule != "required" {
+		t.Error("failing fields out of order !", errs)
+	}
+
+	err = ValidateStruct(&target, FailFast())
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 || errs[0].Error() != "t_int is required." {
+		t.Error("FailFast does not stop at the first field !", err)
+	}
+
+	target = bindRequestStruct{TInt: 1, TInt8: 1, TInt16: 1, TInt32: 1, TInt64: 1, TFloat32: 1, TFloat64: 1, TBool: true, TString: "a", TUnsupported: 1}
+	if err := ValidateStruct(&target); err != nil {
+		t.Error("valid struct reported !", err)
+	}
+}
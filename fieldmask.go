@@ -0,0 +1,123 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldMask is the set of json paths present in a bound request, nested
+// paths joined by ".", e.g. "address.street".
+type FieldMask map[string]bool
+
+// Has reports whether path, or any path nested under it, was present.
+func (m FieldMask) Has(path string) bool {
+	return m[path] || m.hasNested(path)
+}
+
+func (m FieldMask) hasNested(path string) bool {
+	for p := range m {
+		if strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Paths returns the paths of the mask in sorted order.
+func (m FieldMask) Paths() []string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (m FieldMask) add(path string) {
+	if m == nil {
+		return
+	}
+	m[path] = true
+}
+
+// addJSON records the path of every object key in body.
+func (m FieldMask) addJSON(body []byte) {
+	if m == nil {
+		return
+	}
+	var doc interface{}
+	if json.Unmarshal(body, &doc) != nil {
+		return
+	}
+	m.addObject(doc, "")
+}
+
+func (m FieldMask) addObject(doc interface{}, prefix string) {
+	object, ok := doc.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, value := range object {
+		m.add(prefix + key)
+		m.addObject(value, prefix+key+".")
+	}
+}
+
+// ApplyMask copies the fields of from whose path is in mask onto target,
+// leaving every other field of target untouched. Nested structs with masked
+// paths under them are merged rather than replaced. Paths use JSON keys (`json`
+// tag name, then field name).
+func ApplyMask(mask FieldMask, from interface{}, target interface{}) error {
+	dst := reflect.ValueOf(target)
+	if dst.Kind() != reflect.Ptr {
+		return errors.New("Target must be a pointer.")
+	}
+	src := reflect.ValueOf(from)
+	if src.Kind() == reflect.Ptr {
+		src = src.Elem()
+	}
+	dst = dst.Elem()
+	if src.Type() != dst.Type() {
+		return errors.New("Source and target must have the same type.")
+	}
+	applyMask(mask, src, dst, "")
+	return nil
+}
+
+func applyMask(mask FieldMask, src, dst reflect.Value, prefix string) {
+	for i := 0; i < dst.NumField(); i++ {
+		typeField := dst.Type().Field(i)
+		key, ok := jsonKey(typeField)
+		if !ok {
+			continue
+		}
+
+		path := prefix + key
+		if !mask.Has(path) {
+			continue
+		}
+		if mask[path] && !mask.hasNested(path) {
+			dst.Field(i).Set(src.Field(i))
+			continue
+		}
+
+		switch typeField.Type.Kind() {
+		case reflect.Struct:
+			applyMask(mask, src.Field(i), dst.Field(i), path+".")
+		case reflect.Ptr:
+			if src.Field(i).IsNil() || typeField.Type.Elem().Kind() != reflect.Struct {
+				dst.Field(i).Set(src.Field(i))
+				continue
+			}
+			if dst.Field(i).IsNil() {
+				dst.Field(i).Set(reflect.New(typeField.Type.Elem()))
+			}
+			applyMask(mask, src.Field(i).Elem(), dst.Field(i).Elem(), path+".")
+		default:
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
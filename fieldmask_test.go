@@ -0,0 +1,127 @@
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type maskAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type maskUser struct {
+	Name    string       `json:"name"`
+	Age     int          `json:"age"`
+	Address maskAddress  `json:"address"`
+	Billing *maskAddress `json:"billing"`
+}
+
+func ExampleApplyMask() {
+	req, _ := http.NewRequest("PATCH", "http://localhost:9000?age=0", nil)
+
+	stored := maskUser{Name: "Arya", Age: 14}
+	var patch maskUser
+	mask := FieldMask{}
+	err := BindRequest(req, &patch, WithFieldMask(mask))
+	if err != nil {
+		fmt.Println(err)
+	}
+	ApplyMask(mask, patch, &stored)
+	fmt.Println(mask.Paths(), stored.Name, stored.Age)
+	// Output: [age] Arya 0
+}
+
+func TestFieldMaskJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name": "", "address": {"city": "Braavos"}, "billing": {"street": "Canal"}}`)))
+
+	var patch maskUser
+	mask := FieldMask{}
+	if err := BindRequest(req, &patch, WithFieldMask(mask)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"address", "address.city", "billing", "billing.street", "name"}
+	if !reflect.DeepEqual(mask.Paths(), want) {
+		t.Error("json mask mismatch !", mask.Paths())
+	}
+	if !mask.Has("address") || mask.Has("age") {
+		t.Error("mask lookup mismatch !")
+	}
+
+	stored := maskUser{
+		Name:    "Arya",
+		Age:     14,
+		Address: maskAddress{Street: "Kingsroad", City: "Winterfell"},
+		Billing: &maskAddress{City: "Winterfell"},
+	}
+	if err := ApplyMask(mask, &patch, &stored); err != nil {
+		t.Fatal(err)
+	}
+	expected := maskUser{
+		Name:    "",
+		Age:     14,
+		Address: maskAddress{Street: "Kingsroad", City: "Braavos"},
+		Billing: &maskAddress{Street: "Canal", City: "Winterfell"},
+	}
+	if !reflect.DeepEqual(stored, expected) {
+		t.Error("masked apply mismatch !", stored)
+	}
+}
+
+func TestFieldMaskForm(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+	req.Form = url.Values{"name": {"Arya"}, "unknown": {"x"}}
+
+	var patch struct {
+		Name string `json:"name"`
+		Age  int    `json:"age" default:"14"`
+	}
+	mask := FieldMask{}
+	if err := BindRequest(req, &patch, WithFieldMask(mask)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(mask.Paths(), []string{"name"}) {
+		t.Error("form mask mismatch !", mask.Paths())
+	}
+
+	if err := ApplyMask(mask, patch, maskUser{}); err == nil {
+		t.Error("value target not rejected !")
+	}
+	if err := ApplyMask(mask, patch, &maskUser{}); err == nil {
+		t.Error("mismatched types not rejected !")
+	}
+}
+
+type maskPatch struct {
+	Name    *string      `json:"name,omitempty"`
+	Age     int          `json:"age,omitempty"`
+	Address *maskAddress `json:"address,omitempty"`
+}
+
+func TestFieldMaskOmitEmpty(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name": "Sansa", "address": {"city": "Winterfell"}}`)))
+
+	var patch maskPatch
+	mask := FieldMask{}
+	if err := BindRequest(req, &patch, WithFieldMask(mask)); err != nil {
+		t.Fatal(err)
+	}
+
+	name := "Arya"
+	stored := maskPatch{Name: &name, Age: 14, Address: &maskAddress{Street: "Kingsroad", City: "Braavos"}}
+	if err := ApplyMask(mask, &patch, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if *stored.Name != "Sansa" || stored.Age != 14 || *stored.Address != (maskAddress{Street: "Kingsroad", City: "Winterfell"}) {
+		t.Error("omitempty mask not applied !", *stored.Name, stored.Age, *stored.Address)
+	}
+}
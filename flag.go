@@ -0,0 +1,110 @@
+package structs
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// BindFlags will scan your struct and register one flag per field on fs
+// according to `flag` tag on struct, with `usage` tag as its help text.
+// Parsed values land directly in your struct, the `default` tag (or the
+// current field value) is used as the flag default.
+// Nested structs register their flags as "<prefix>.<name>".
+func BindFlags(fs *flag.FlagSet, target interface{}) error {
+	val := reflect.ValueOf(target)
+
+	if val.Kind() != reflect.Ptr {
+		return errors.New("Target can't be value")
+	}
+	return bindFlags(fs, val.Elem(), "")
+}
+
+// ParseFlags binds target into fs, parses args and validates the result.
+func ParseFlags(fs *flag.FlagSet, target interface{}, args []string) error {
+	if err := BindFlags(fs, target); err != nil {
+		return err
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return ValidateStruct(target)
+}
+
+func bindFlags(fs *flag.FlagSet, val reflect.Value, prefix string) error {
+	if err := setDefaults(val, "flag", nil); err != nil {
+		return err
+	}
+
+	for i := 0; i < val.NumField(); i++ {
+		typeField := val.Type().Field(i)
+		tag := typeField.Tag.Get("flag")
+		if tag == "-" || typeField.PkgPath != "" {
+			continue
+		}
+
+		if typeField.Type.Kind() == reflect.Struct {
+			if tag == "" {
+				tag = typeField.Name
+			}
+			if err := bindFlags(fs, val.Field(i), flagName(prefix, tag)); err != nil {
+				return err
+			}
+			continue
+		}
+		if tag == "" {
+			continue
+		}
+
+		name := flagName(prefix, tag)
+		// Probe the conversion so unsupported fields fail at registration
+		// rather than when the flag is first used.
+		probe := reflect.New(typeField.Type).Elem()
+		if setter, ok := asPresenceSetter(probe); ok {
+			probe = setter.set()
+		}
+		if _, ok := typeField.Tag.Lookup("split"); ok && probe.Kind() == reflect.Slice {
+			probe = reflect.New(probe.Type().Elem()).Elem()
+		}
+		enum, err := enumOf(typeField)
+		if err != nil {
+			return err
+		}
+		if err := convertValue(probe, enum, "1"); err == errUnsupportedType {
+			return fieldError(err, name, typeField.Type.String(), "flag")
+		}
+		fs.Var(&fieldValue{field: val.Field(i), typeField: typeField, name: name}, name, typeField.Tag.Get("usage"))
+	}
+	return nil
+}
+
+func flagName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// fieldValue is a flag.Value storing into a struct field through bindValue.
+type fieldValue struct {
+	field     reflect.Value
+	typeField reflect.StructField
+	name      string
+}
+
+func (v *fieldValue) String() string {
+	if v == nil || !v.field.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(v.field.Interface())
+}
+
+func (v *fieldValue) Set(value string) error {
+	return bindValue(v.field, v.typeField, v.name, value, "flag")
+}
+
+// IsBoolFlag lets bool fields be set with a bare -name.
+func (v *fieldValue) IsBoolFlag() bool {
+	return v.field.IsValid() && v.field.Kind() == reflect.Bool
+}
@@ -0,0 +1,85 @@
+package structs
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+)
+
+type flagConfig struct {
+	Name    string  `flag:"name" usage:"service name" required:"true"`
+	Port    int     `flag:"port" usage:"listen port" default:"8080"`
+	Verbose bool    `flag:"v" usage:"verbose output"`
+	Ratio   float32 `flag:"ratio"`
+	Ignored string
+	DB      struct {
+		Host string `flag:"host" default:"localhost"`
+	} `flag:"db"`
+}
+
+func ExampleParseFlags() {
+	var cfg struct {
+		Name string `flag:"name" usage:"service name" required:"true"`
+		Port int    `flag:"port" usage:"listen port" default:"8080"`
+	}
+	fs := flag.NewFlagSet("example", flag.ContinueOnError)
+	err := ParseFlags(fs, &cfg, []string{"-name", "structs"})
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(cfg.Name, cfg.Port)
+	// Output: structs 8080
+}
+
+func TestBindFlags(t *testing.T) {
+	var cfg flagConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := BindFlags(fs, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if f := fs.Lookup("port"); f == nil || f.Usage != "listen port" || f.DefValue != "8080" {
+		t.Error("port flag not registered !")
+	}
+	if fs.Lookup("Ignored") != nil {
+		t.Error("untagged field registered !")
+	}
+
+	err := fs.Parse([]string{"-name", "api", "-v", "-ratio", "1.5", "-db.host", "db.local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "api" || cfg.Port != 8080 || !cfg.Verbose || cfg.Ratio != 1.5 || cfg.DB.Host != "db.local" {
+		t.Error("flag values mismatch !", cfg)
+	}
+}
+
+func TestParseFlagsValidate(t *testing.T) {
+	var cfg flagConfig
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	err := ParseFlags(fs, &cfg, []string{"-port", "9000"})
+	if err == nil || err.Error() != "Name is required." {
+		t.Error("flags not validated !", err)
+	}
+
+	var unsupported struct {
+		C complex64 `flag:"c"`
+	}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := BindFlags(fs, &unsupported); err == nil {
+		t.Error("unsupported flag type not reported !")
+	}
+}
+
+func TestBindFlagsSplit(t *testing.T) {
+	var cfg struct {
+		Hosts []string `flag:"hosts" split:","`
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := ParseFlags(fs, &cfg, []string{"-hosts", "a,b"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Hosts) != 2 || cfg.Hosts[1] != "b" {
+		t.Error("split flag mismatch !", cfg.Hosts)
+	}
+}
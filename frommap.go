@@ -0,0 +1,168 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// FromMap fills your struct from a map, as the inverse of ToMap.
+// Keys follow the same rules as ToMap (`json` tag, then field name) and
+// values are converted weakly, so the strings produced by ToMap(v, true)
+// decode back into numbers and bools. A round trip through either is
+// lossless.
+func FromMap(m map[string]interface{}, target interface{}) error {
+	val := reflect.ValueOf(target)
+
+	if val.Kind() != reflect.Ptr {
+		return errors.New("Target can't be value")
+	}
+	return fromMap(m, val.Elem())
+}
+
+func fromMap(m map[string]interface{}, val reflect.Value) error {
+	for i := 0; i < val.NumField(); i++ {
+		typeField := val.Type().Field(i)
+		tag := typeField.Tag.Get("json")
+		if tag == "-" || typeField.PkgPath != "" {
+			continue
+		}
+
+		key := tag
+		if key == "" {
+			key = typeField.Name
+		}
+		in, ok := m[key]
+		if !ok {
+			continue
+		}
+		if enum, _ := enumOf(typeField); enum != nil {
+			if name, ok := in.(string); ok {
+				if err := enum.set(val.Field(i), name); err != nil {
+					return fieldError(err, key, typeField.Type.String(), "json")
+				}
+				continue
+			}
+			if names, ok := stringSlice(in); ok && val.Field(i).Kind() == reflect.Slice {
+				if err := enum.setNames(val.Field(i), names, key, "json"); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := decodeValue(val.Field(i), in, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stringSlice returns in as a []string when it is one, or a []interface{}
+// holding only strings.
+func stringSlice(in interface{}) ([]string, bool) {
+	switch in := in.(type) {
+	case []string:
+		return in, true
+	case []interface{}:
+		names := make([]string, len(in))
+		for i, item := range in {
+			name, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			names[i] = name
+		}
+		return names, true
+	}
+	return nil, false
+}
+
+// decodeValue stores in into field, recursing into structs, slices and maps.
+func decodeValue(field reflect.Value, in interface{}, key string) error {
+	if setter, ok := asPresenceSetter(field); ok {
+		if in == nil {
+			setter.setNull()
+			return nil
+		}
+		return decodeValue(setter.set(), in, key)
+	}
+
+	t := field.Type()
+	if in == nil {
+		field.Set(reflect.Zero(t))
+		return nil
+	}
+	if s, ok := in.(string); ok && isBigNumber(t) {
+		if err := setBigNumber(field, s); err != nil {
+			return fieldError(err, key, t.String(), "json")
+		}
+		return nil
+	}
+
+	src := reflect.ValueOf(in)
+	if src.Type().AssignableTo(t) {
+		field.Set(src)
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(t.Elem())
+		if err := decodeValue(elem.Elem(), in, key); err != nil {
+			return err
+		}
+		field.Set(elem)
+	case reflect.Struct:
+		m, ok := in.(map[string]interface{})
+		if !ok {
+			return fieldError(errInvalidValue, key, t.String(), "json")
+		}
+		return fromMap(m, field)
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			return fieldError(errInvalidValue, key, t.String(), "json")
+		}
+		s := reflect.MakeSlice(t, src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := decodeValue(s.Index(i), src.Index(i).Interface(), fmt.Sprintf("%s[%d]", key, i)); err != nil {
+				return err
+			}
+		}
+		field.Set(s)
+	case reflect.Map:
+		if src.Kind() != reflect.Map {
+			return fieldError(errInvalidValue, key, t.String(), "json")
+		}
+		m := reflect.MakeMapWithSize(t, src.Len())
+		for _, k := range src.MapKeys() {
+			mk := reflect.New(t.Key()).Elem()
+			if err := decodeValue(mk, k.Interface(), key); err != nil {
+				return err
+			}
+			mv := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(mv, src.MapIndex(k).Interface(), fmt.Sprintf("%s[%v]", key, k.Interface())); err != nil {
+				return err
+			}
+			m.SetMapIndex(mk, mv)
+		}
+		field.Set(m)
+	case reflect.String:
+		field.SetString(fmt.Sprint(in))
+	default:
+		if s, ok := in.(string); ok {
+			if err := setField(field, s); err != nil {
+				return fieldError(err, key, t.String(), "json")
+			}
+			return nil
+		}
+		if !isNumber(src.Kind()) || !isNumber(t.Kind()) {
+			return fieldError(errInvalidValue, key, t.String(), "json")
+		}
+		field.Set(src.Convert(t))
+	}
+	return nil
+}
+
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
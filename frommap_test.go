@@ -0,0 +1,100 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type fromMapAddress struct {
+	Street      string
+	Number      int32
+	Geolocation struct {
+		Lat float64
+		Lng float32
+	}
+}
+
+type fromMapStruct struct {
+	Name       string `json:"name"`
+	Age        int64
+	Active     bool
+	FavNumbers []int
+	Friends    []struct {
+		Name int32
+	}
+	Tags    map[string]int
+	Address fromMapAddress
+	Home    *fromMapAddress
+	Secret  string `json:"-"`
+}
+
+func ExampleFromMap() {
+	var target struct {
+		Name string `json:"name"`
+		Age  int64
+	}
+	err := FromMap(map[string]interface{}{"name": "Arya Stark", "Age": "14"}, &target)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(target.Name, target.Age)
+	// Output: Arya Stark 14
+}
+
+func TestFromMapRoundTrip(t *testing.T) {
+	source := fromMapStruct{
+		Name:       "Ali",
+		Age:        22,
+		Active:     true,
+		FavNumbers: []int{15, 3, 22},
+		Friends: []struct {
+			Name int32
+		}{{Name: 54}, {Name: 34}},
+		Tags: map[string]int{"a": 1},
+		Home: &fromMapAddress{Street: "Baker St"},
+	}
+	source.Address.Street = "flamboyan"
+	source.Address.Number = 5
+	source.Address.Geolocation.Lat = 0.125
+	source.Address.Geolocation.Lng = 89.2265
+
+	var target fromMapStruct
+	if err := FromMap(ToMap(source), &target); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(source, target) {
+		t.Error("round trip is lossy !", target)
+	}
+
+	strs := ToMap(source, true)
+	geo := strs["Address"].(map[string]interface{})["Geolocation"].(map[string]interface{})
+	if geo["Lat"] != "0.125" || geo["Lng"] != "89.2265" {
+		t.Error("floats not formatted exactly !", geo)
+	}
+
+	target = fromMapStruct{}
+	if err := FromMap(strs, &target); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(source, target) {
+		t.Error("string round trip is lossy !", target)
+	}
+}
+
+func TestFromMapInvalid(t *testing.T) {
+	var target fromMapStruct
+	err := FromMap(map[string]interface{}{"FavNumbers": []interface{}{"1", "two"}}, &target)
+	if err == nil || err.Error() != "FavNumbers[1] is not a valid int." {
+		t.Error("invalid element not reported !", err)
+	}
+
+	err = FromMap(map[string]interface{}{"Address": "flamboyan"}, &target)
+	if err == nil {
+		t.Error("invalid struct not reported !")
+	}
+
+	if err := FromMap(map[string]interface{}{}, target); err == nil {
+		t.Error("value target not rejected !")
+	}
+}
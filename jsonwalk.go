@@ -0,0 +1,164 @@
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// jsonAction tells rewriteJSON what to do with the value of a field.
+type jsonAction int
+
+const (
+	jsonWalk    jsonAction = iota // walk into the value
+	jsonDrop                      // remove the key
+	jsonReplace                   // use the returned value instead
+)
+
+// jsonVisitor is called by rewriteJSON for every struct field present in the
+// document, with its path spelled as in the document like FieldMask ones.
+type jsonVisitor func(typeField reflect.StructField, path string, msg json.RawMessage) (json.RawMessage, jsonAction, error)
+
+// rewriteJSON walks data, a JSON document decoded into type t at path, calling
+// visit for every struct field it holds, and returns data rewritten as visit
+// asks. Keys are matched to fields ignoring case, as encoding/json does.
+// Interfaces are walked as the variant named by their disc key. data is
+// returned untouched when nothing changed.
+func rewriteJSON(t reflect.Type, disc string, data json.RawMessage, path string, visit jsonVisitor) (json.RawMessage, bool, error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return rewriteJSON(t.Elem(), disc, data, path, visit)
+	case reflect.Interface:
+		if variant := variantType(t, disc, data); variant != nil {
+			return rewriteJSON(variant, "", data, path, visit)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return data, false, nil
+		}
+		changed := false
+		for i, item := range items {
+			rewritten, ok, err := rewriteJSON(t.Elem(), disc, item, fmt.Sprintf("%s[%d]", path, i), visit)
+			if err != nil {
+				return data, false, err
+			}
+			if ok {
+				items[i], changed = rewritten, true
+			}
+		}
+		return remarshal(data, items, changed)
+	case reflect.Map:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return data, false, nil
+		}
+		changed := false
+		for key, item := range object {
+			rewritten, ok, err := rewriteJSON(t.Elem(), disc, item, path+"."+key, visit)
+			if err != nil {
+				return data, false, err
+			}
+			if ok {
+				object[key], changed = rewritten, true
+			}
+		}
+		return remarshal(data, object, changed)
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return data, false, nil
+		}
+		changed, err := rewriteObject(t, object, path, visit)
+		if err != nil {
+			return data, false, err
+		}
+		return remarshal(data, object, changed)
+	}
+	return data, false, nil
+}
+
+// rewriteObject rewrites object, holding the fields of the struct type t.
+// Embedded structs share object.
+func rewriteObject(t reflect.Type, object map[string]json.RawMessage, path string, visit jsonVisitor) (bool, error) {
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		typeField := t.Field(i)
+		if embedded := typeField.Type; typeField.Anonymous && typeField.Tag.Get("json") == "" {
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				ok, err := rewriteObject(embedded, object, path, visit)
+				if err != nil {
+					return false, err
+				}
+				changed = ok || changed
+				continue
+			}
+		}
+		key, ok := jsonKey(typeField)
+		if !ok {
+			continue
+		}
+
+		for name, msg := range object {
+			if !strings.EqualFold(name, key) {
+				continue
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+
+			rewritten, action, err := visit(typeField, fieldPath, msg)
+			if err == nil && action == jsonWalk {
+				var ok bool
+				rewritten, ok, err = rewriteJSON(typeField.Type, typeField.Tag.Get("discriminator"), msg, fieldPath, visit)
+				if ok {
+					action = jsonReplace
+				}
+			}
+			if err != nil {
+				return false, err
+			}
+
+			switch action {
+			case jsonDrop:
+				delete(object, name)
+				changed = true
+			case jsonReplace:
+				object[name], changed = rewritten, true
+			}
+		}
+	}
+	return changed, nil
+}
+
+// variantType returns the variant of the interface type t named by the disc
+// key of msg, or nil. Unknown variants are reported by decodeVariant once the
+// body is bound.
+func variantType(t reflect.Type, disc string, msg json.RawMessage) reflect.Type {
+	var head map[string]json.RawMessage
+	var name string
+	if disc == "" || json.Unmarshal(msg, &head) != nil || json.Unmarshal(head[disc], &name) != nil {
+		return nil
+	}
+
+	variantsMu.RLock()
+	defer variantsMu.RUnlock()
+	return variants[t][name]
+}
+
+// remarshal encodes v in place of data when changed is set.
+func remarshal(data json.RawMessage, v interface{}, changed bool) (json.RawMessage, bool, error) {
+	if !changed {
+		return data, false, nil
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return data, false, nil
+	}
+	return encoded, true, nil
+}
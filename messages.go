@@ -0,0 +1,165 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Translator turns a FieldError into a message, returning "" when it has
+// none for it.
+type Translator interface {
+	Translate(fe *FieldError) string
+}
+
+// Bundle maps rule names to message templates. `min`, `max` failing on
+// anything but a number, e.g. a string, slice or map, use the `min_length`
+// and `max_length` templates, `required_*` rules fall back to `required` and
+// rules without a template use `default`. Templates can use {field}, {rule},
+// {param}, {list} (param as a comma separated list) and {value}.
+type Bundle map[string]string
+
+var (
+	bundlesMu sync.RWMutex
+	bundles   = map[string]Bundle{
+		"en": {
+			"required":   "{field} is required.",
+			"min":        "{field} must be at least {param}.",
+			"min_length": "{field} must have a length of at least {param}.",
+			"max":        "{field} must be at most {param}.",
+			"max_length": "{field} must have a length of at most {param}.",
+			"len":        "{field} must have a length of {param}.",
+			"regex":      "{field} must match {param}.",
+			"oneof":      "{field} must be one of {list}.",
+			"enum":       "{field} must be one of {param}.",
+			"eqfield":    "{field} must be equal to {param}.",
+			"nefield":    "{field} must not be equal to {param}.",
+			"gtfield":    "{field} must be greater than {param}.",
+			"ltfield":    "{field} must be less than {param}.",
+			"default":    "{field} failed the {rule} rule.",
+		},
+		"id": {
+			"required":   "{field} wajib diisi.",
+			"min":        "{field} minimal {param}.",
+			"min_length": "Panjang {field} minimal {param}.",
+			"max":        "{field} maksimal {param}.",
+			"max_length": "Panjang {field} maksimal {param}.",
+			"len":        "Panjang {field} harus {param}.",
+			"regex":      "{field} harus sesuai dengan {param}.",
+			"oneof":      "{field} harus salah satu dari {list}.",
+			"enum":       "{field} harus salah satu dari {param}.",
+			"eqfield":    "{field} harus sama dengan {param}.",
+			"nefield":    "{field} tidak boleh sama dengan {param}.",
+			"gtfield":    "{field} harus lebih besar dari {param}.",
+			"ltfield":    "{field} harus lebih kecil dari {param}.",
+			"default":    "{field} tidak memenuhi aturan {rule}.",
+		},
+	}
+)
+
+// RegisterBundle adds the templates of b to locale, replacing the ones
+// already registered under the same keys. Templates registered to "en" are
+// used by Error.
+func RegisterBundle(locale string, b Bundle) {
+	bundlesMu.Lock()
+	defer bundlesMu.Unlock()
+	if bundles[locale] == nil {
+		bundles[locale] = Bundle{}
+	}
+	for key, tmpl := range b {
+		bundles[locale][key] = tmpl
+	}
+}
+
+// Locale returns a Translator using the templates registered to locale,
+// falling back to "en" ones.
+func Locale(locale string) Translator {
+	return localeTranslator(locale)
+}
+
+type localeTranslator string
+
+func (l localeTranslator) Translate(fe *FieldError) string {
+	bundlesMu.RLock()
+	defer bundlesMu.RUnlock()
+	return translate(fe, bundles[string(l)], bundles["en"])
+}
+
+// translate renders the template of fe from the first bundle having it.
+// A `msg` tag is looked up as a key first, then used as the template itself.
+// Errors carrying the text of an error only use a template of their rule.
+func translate(fe *FieldError, bundles ...Bundle) string {
+	keys := []string{fe.msg}
+	if fe.msg == "" {
+		keys = messageKeys(fe)
+	}
+	for _, key := range keys {
+		for _, b := range bundles {
+			if tmpl, ok := b[key]; ok {
+				return render(tmpl, fe)
+			}
+		}
+	}
+
+	if fe.msg != "" {
+		return render(fe.msg, fe)
+	}
+	return ""
+}
+
+// messageKeys lists the bundle keys for fe, most specific first.
+func messageKeys(fe *FieldError) []string {
+	key := fe.Rule
+	if (key == "min" || key == "max") && fe.Value != nil {
+		if value, _ := resolve(reflect.ValueOf(fe.Value)); value.IsValid() {
+			if _, ok := numberRat(value); !ok {
+				key += "_length"
+			}
+		}
+	}
+
+	keys := []string{key}
+	if strings.HasPrefix(key, "required_") {
+		keys = append(keys, "required")
+	}
+	if !fe.raw {
+		keys = append(keys, "default")
+	}
+	return keys
+}
+
+func render(tmpl string, fe *FieldError) string {
+	value, ok := bigString(fe.Value)
+	if !ok && fe.Value != nil {
+		if v, _ := resolve(reflect.ValueOf(fe.Value)); v.IsValid() {
+			value = fmt.Sprint(v.Interface())
+		}
+	}
+	return strings.NewReplacer(
+		"{field}", fe.Path,
+		"{rule}", fe.Rule,
+		"{param}", fe.Param,
+		"{list}", strings.Join(strings.Fields(fe.Param), ", "),
+		"{value}", value,
+	).Replace(tmpl)
+}
+
+// Translate returns the message of e in tr, or Error when tr has none.
+func (e *FieldError) Translate(tr Translator) string {
+	if message := tr.Translate(e); message != "" {
+		return message
+	}
+	return e.Error()
+}
+
+// Translate returns a copy of e whose messages are translated by tr.
+func (e ValidationErrors) Translate(tr Translator) ValidationErrors {
+	translated := make(ValidationErrors, len(e))
+	for i, fe := range e {
+		copied := *fe
+		copied.message = fe.Translate(tr)
+		translated[i] = &copied
+	}
+	return translated
+}
@@ -0,0 +1,75 @@
+package structs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func ExampleLocale() {
+	signup := struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required" msg:"Please enter your {field}."`
+		Age   int    `json:"age" validate:"min=18"`
+	}{Age: 12}
+
+	err := ValidateStruct(&signup).(ValidationErrors)
+	fmt.Println(err)
+	fmt.Println(err.Translate(Locale("id")))
+	// Output: name is required. Please enter your email. age must be at least 18.
+	// name wajib diisi. Please enter your email. age minimal 18.
+}
+
+type messageForm struct {
+	Name     string   `json:"name" validate:"min=3"`
+	Tags     []string `json:"tags" validate:"dive,oneof=go rust"`
+	Nickname string   `json:"nickname" validate:"required_with=name" msg:"nickname_needed"`
+	Code     string   `json:"code" validate:"message_code"`
+	Email    string   `json:"email" validate:"message_taken"`
+}
+
+func TestMessages(t *testing.T) {
+	RegisterRule("message_code", func(fl FieldContext) bool { return false })
+	RegisterRuleCtx("message_taken", func(_ context.Context, fl FieldContext) error {
+		return errors.New(fl.Path + " is taken.")
+	})
+	RegisterBundle("en", Bundle{"nickname_needed": "Tell us what {field} to call you."})
+	RegisterBundle("id", Bundle{"nickname_needed": "Nama panggilan wajib diisi.", "message_taken": "{field} sudah dipakai."})
+
+	form := messageForm{Name: "Al", Tags: []string{"go", "java"}, Code: "x", Email: "a@b.c"}
+	err, ok := ValidateStruct(&form).(ValidationErrors)
+	if !ok {
+		t.Fatal("validation errors expected !", err)
+	}
+
+	expected := "name must have a length of at least 3. tags[1] must be one of go, rust. Tell us what nickname to call you. " +
+		"code failed the message_code rule. email is taken."
+	if err.Error() != expected {
+		t.Error("english messages mismatch !", err)
+	}
+
+	expected = "Panjang name minimal 3. tags[1] harus salah satu dari go, rust. Nama panggilan wajib diisi. " +
+		"code tidak memenuhi aturan message_code. email sudah dipakai."
+	if translated := err.Translate(Locale("id")); translated.Error() != expected {
+		t.Error("indonesian messages mismatch !", translated)
+	}
+	if err.Error() == err.Translate(Locale("id")).Error() {
+		t.Error("Translate changed the original errors !")
+	}
+
+	if msg := err[0].Translate(Locale("fr")); msg != "name must have a length of at least 3." {
+		t.Error("unknown locale does not fall back to english !", msg)
+	}
+
+	fe := &FieldError{Path: "age", Rule: "max", Param: "10", Value: 12}
+	if fe.Error() != "age must be at most 10." || fe.Translate(Locale("id")) != "age maksimal 10." {
+		t.Error("external FieldError messages mismatch !", fe, fe.Translate(Locale("id")))
+	}
+
+	RegisterBundle("en", Bundle{"max": "{field} can't exceed {param}, got {value}."})
+	defer RegisterBundle("en", Bundle{"max": "{field} must be at most {param}."})
+	if fe.Error() != "age can't exceed 10, got 12." {
+		t.Error("registered template not used !", fe)
+	}
+}
@@ -0,0 +1,211 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+var jsonNull = []byte("null")
+
+// Optional holds a value that may be absent from the input, for PATCH
+// semantics. JSON null counts as absent, use Nullable to tell them apart.
+//
+// BindRequest marks it present when its key is sent, ValidateStruct treats
+// `required` as present, and ToMap omits it while absent.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// Some returns an Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Present: true}
+}
+
+// Get returns the value and whether it is present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+// IsZero reports whether the value is absent, so `omitzero` drops it.
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present {
+		return jsonNull, nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	*o = Optional[T]{}
+	if bytes.Equal(bytes.TrimSpace(data), jsonNull) {
+		return nil
+	}
+	if err := json.Unmarshal(data, &o.Value); err != nil {
+		return err
+	}
+	o.Present = true
+	return nil
+}
+
+func (o Optional[T]) isPresent() bool  { return o.Present }
+func (o Optional[T]) isNull() bool     { return false }
+func (o Optional[T]) get() interface{} { return o.Value }
+func (o *Optional[T]) setNull()        { *o = Optional[T]{} }
+func (o *Optional[T]) set() reflect.Value {
+	o.Present = true
+	return reflect.ValueOf(&o.Value).Elem()
+}
+
+// Nullable holds a value that may be absent, explicitly null or set.
+// Besides the behaviour of Optional, ToMap keeps an explicit null as nil
+// and BindRequest reads an empty form value as null.
+type Nullable[T any] struct {
+	Value   T
+	Valid   bool
+	Present bool
+}
+
+// NullableOf returns a present, non null Nullable holding v.
+func NullableOf[T any](v T) Nullable[T] {
+	return Nullable[T]{Value: v, Valid: true, Present: true}
+}
+
+// Null returns a present Nullable holding an explicit null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Present: true}
+}
+
+// Get returns the value and whether it is present and not null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.Value, n.Valid
+}
+
+// IsNull reports whether the value was sent as an explicit null.
+func (n Nullable[T]) IsNull() bool {
+	return n.Present && !n.Valid
+}
+
+// IsZero reports whether the value is absent, so `omitzero` drops it.
+func (n Nullable[T]) IsZero() bool {
+	return !n.Present
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.Value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	*n = Nullable[T]{Present: true}
+	if bytes.Equal(bytes.TrimSpace(data), jsonNull) {
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.Value); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n Nullable[T]) isPresent() bool  { return n.Present }
+func (n Nullable[T]) isNull() bool     { return n.Present && !n.Valid }
+func (n Nullable[T]) get() interface{} { return n.Value }
+func (n *Nullable[T]) setNull()        { *n = Nullable[T]{Present: true} }
+func (n *Nullable[T]) set() reflect.Value {
+	n.Present, n.Valid = true, true
+	return reflect.ValueOf(&n.Value).Elem()
+}
+
+// presence is implemented by Optional and Nullable.
+type presence interface {
+	isPresent() bool
+	isNull() bool
+	get() interface{}
+}
+
+// presenceSetter is implemented by *Optional and *Nullable, set marks the
+// value present and returns it for binding.
+type presenceSetter interface {
+	setNull()
+	set() reflect.Value
+}
+
+// asPresenceSetter returns field as a presenceSetter when it is an Optional
+// or a Nullable.
+func asPresenceSetter(field reflect.Value) (presenceSetter, bool) {
+	if !field.CanAddr() {
+		return nil, false
+	}
+	setter, ok := field.Addr().Interface().(presenceSetter)
+	return setter, ok
+}
+
+var presenceSetterType = reflect.TypeOf((*presenceSetter)(nil)).Elem()
+
+// presenceElem returns the type of the value held by t when t is an Optional
+// or a Nullable.
+func presenceElem(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct || !reflect.PointerTo(t).Implements(presenceSetterType) {
+		return nil, false
+	}
+	typeField, _ := t.FieldByName("Value")
+	return typeField.Type, true
+}
+
+// pruneAbsent removes from the JSON encoding of val the keys of every absent
+// Optional and Nullable, which json.Marshal can only write as null.
+func pruneAbsent(val reflect.Value, data []byte) []byte {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return data
+		}
+		return pruneAbsent(val.Elem(), data)
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil || len(items) != val.Len() {
+			return data
+		}
+		for i := range items {
+			items[i] = pruneAbsent(val.Index(i), items[i])
+		}
+		if pruned, err := json.Marshal(items); err == nil {
+			return pruned
+		}
+	case reflect.Struct:
+		if _, ok := val.Interface().(json.Marshaler); ok {
+			return data
+		}
+		var raw map[string]json.RawMessage
+		if json.Unmarshal(data, &raw) != nil {
+			return data
+		}
+		for i := 0; i < val.NumField(); i++ {
+			typeField := val.Type().Field(i)
+			key, ok := jsonKey(typeField)
+			if !ok {
+				continue
+			}
+			msg, ok := raw[key]
+			if !ok {
+				continue
+			}
+			if p, ok := val.Field(i).Interface().(presence); ok && !p.isPresent() {
+				delete(raw, key)
+				continue
+			}
+			raw[key] = pruneAbsent(val.Field(i), msg)
+		}
+		if pruned, err := json.Marshal(raw); err == nil {
+			return pruned
+		}
+	}
+	return data
+}
@@ -0,0 +1,126 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type optionalPatch struct {
+	Name     Optional[string] `json:"name" required:"true"`
+	Age      Optional[int]    `json:"age"`
+	Nickname Nullable[string] `json:"nickname"`
+	Height   Nullable[int]    `json:"height"`
+}
+
+func ExampleNullable() {
+	req, _ := http.NewRequest("POST", "", bytes.NewReader([]byte(`{"name": "Arya", "nickname": null}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	var patch optionalPatch
+	err := BindRequest(req, &patch)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(patch.Name.Present, patch.Age.Present, patch.Nickname.IsNull())
+	fmt.Println(ToMap(patch))
+	// Output: true false true
+	// map[name:Arya nickname:<nil>]
+}
+
+func TestOptionalJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"age": 14, "nickname": "No One", "height": null}`)))
+
+	var patch optionalPatch
+	if err := BindRequest(req, &patch); err != nil {
+		t.Fatal(err)
+	}
+	if patch.Name.Present || !patch.Age.Present || patch.Age.Value != 14 {
+		t.Error("optional json mismatch !", patch)
+	}
+	if v, ok := patch.Nickname.Get(); !ok || v != "No One" {
+		t.Error("nullable value mismatch !", patch.Nickname)
+	}
+	if !patch.Height.IsNull() {
+		t.Error("nullable null mismatch !", patch.Height)
+	}
+
+	data, _ := json.Marshal(optionalPatch{Name: Some("Arya"), Height: Null[int]()})
+	if string(data) != `{"name":"Arya","age":null,"nickname":null,"height":null}` {
+		t.Error("optional marshal mismatch !", string(data))
+	}
+}
+
+func TestOptionalForm(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+	req.Form = url.Values{"name": {"Arya"}, "nickname": {""}, "height": {"150"}}
+
+	var patch optionalPatch
+	if err := BindRequest(req, &patch); err != nil {
+		t.Fatal(err)
+	}
+	if patch.Name != Some("Arya") || patch.Age.Present {
+		t.Error("optional form mismatch !", patch)
+	}
+	if !patch.Nickname.IsNull() || patch.Height != NullableOf(150) {
+		t.Error("nullable form mismatch !", patch)
+	}
+}
+
+func TestOptionalValidateAndMap(t *testing.T) {
+	patch := optionalPatch{Age: Some(0)}
+	if err := ValidateStruct(&patch); err == nil || err.Error() != "name is required." {
+		t.Error("absent optional passes required !", err)
+	}
+	patch.Name = Some("")
+	if err := ValidateStruct(&patch); err != nil {
+		t.Error("present optional fails required !", err)
+	}
+
+	result := ToMap(patch, true)
+	if !reflect.DeepEqual(result, map[string]interface{}{"name": "", "age": "0"}) {
+		t.Error("optional map mismatch !", result)
+	}
+
+	var back optionalPatch
+	if err := FromMap(map[string]interface{}{"age": "3", "height": nil}, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Age != Some(3) || !back.Height.IsNull() || back.Name.Present {
+		t.Error("optional from map mismatch !", back)
+	}
+}
+
+func TestCopyOptional(t *testing.T) {
+	from := optionalPatch{Name: Some("Arya"), Height: Null[int]()}
+	var to optionalPatch
+	if err := Copy(from, &to); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(from, to) {
+		t.Error("copy optional mismatch !", to)
+	}
+}
+
+func TestOptionalNilInterface(t *testing.T) {
+	target := struct {
+		Meta Optional[any]   `json:"meta" validate:"required,min=1"`
+		Err  Optional[error] `json:"err" validate:"required"`
+	}{Meta: Some[any](nil), Err: Some[error](nil)}
+
+	if err := ValidateStruct(&target); err != nil {
+		t.Error("present nil optional rejected !", err)
+	}
+
+	target.Err = Optional[error]{}
+	if err := ValidateStruct(&target); err == nil || err.Error() != "err is required." {
+		t.Error("absent optional error mismatch !", err)
+	}
+}
@@ -0,0 +1,127 @@
+package structs
+
+// BindOption configures a single BindRequest call.
+type BindOption func(*bindOptions)
+
+type bindOptions struct {
+	report      BindReport
+	ignoreCase  bool
+	mask        FieldMask
+	maxBodySize int64
+	strict      bool
+	allow       []string
+
+	allowFields     map[string]bool
+	rejectProtected bool
+}
+
+func newBindOptions(opts []BindOption) *bindOptions {
+	options := &bindOptions{maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithReport records in report where BindRequest took every field from.
+func WithReport(report BindReport) BindOption {
+	return func(o *bindOptions) {
+		o.report = report
+	}
+}
+
+// WithFieldMask records in mask the json path of every field present in the
+// request, so PATCH handlers can tell sent fields from zero values.
+func WithFieldMask(mask FieldMask) BindOption {
+	return func(o *bindOptions) {
+		o.mask = mask
+	}
+}
+
+// MaxBodySize limits the decoded size of JSON request bodies to n bytes,
+// n <= 0 removes the limit.
+func MaxBodySize(n int64) BindOption {
+	return func(o *bindOptions) {
+		o.maxBodySize = n
+	}
+}
+
+// CaseInsensitive matches request keys against `json` and `alias` tags
+// ignoring case, after every exact match has been tried.
+func CaseInsensitive() BindOption {
+	return func(o *bindOptions) {
+		o.ignoreCase = true
+	}
+}
+
+// Strict makes BindRequest fail with an *UnknownParamsError when the query
+// string or form carries keys matching no field. allow lists keys, or
+// path.Match patterns such as "utm_*", that are accepted anyway.
+func Strict(allow ...string) BindOption {
+	return func(o *bindOptions) {
+		o.strict = true
+		o.allow = append(o.allow, allow...)
+	}
+}
+
+// AllowFields lets this call set only the fields whose path is listed, e.g.
+// "name" or "profile.email". Listing a struct allows every field under it,
+// every other field is treated as protected.
+func AllowFields(keys ...string) BindOption {
+	return func(o *bindOptions) {
+		if o.allowFields == nil {
+			o.allowFields = map[string]bool{}
+		}
+		for _, key := range keys {
+			o.allowFields[key] = true
+		}
+	}
+}
+
+// RejectProtected makes BindRequest fail when the request carries a value
+// for a protected field, instead of silently ignoring it.
+func RejectProtected() BindOption {
+	return func(o *bindOptions) {
+		o.rejectProtected = true
+	}
+}
+
+// ValidateOption configures a single ValidateStruct call.
+type ValidateOption func(*validateOptions)
+
+type validateOptions struct {
+	failFast        bool
+	concurrency     int
+	pointerPresence bool
+}
+
+func newValidateOptions(opts []ValidateOption) *validateOptions {
+	options := &validateOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// FailFast makes ValidateStruct stop at the first failing field.
+func FailFast() ValidateOption {
+	return func(o *validateOptions) {
+		o.failFast = true
+	}
+}
+
+// Concurrency lets ValidateStructCtx run the context rules of up to n fields
+// at once. Rules of the same field still run in order.
+func Concurrency(n int) ValidateOption {
+	return func(o *validateOptions) {
+		o.concurrency = n
+	}
+}
+
+// PointerPresence makes `required` accept any non-nil pointer, even one
+// pointing at a zero value.
+func PointerPresence() ValidateOption {
+	return func(o *validateOptions) {
+		o.pointerPresence = true
+	}
+}
@@ -0,0 +1,79 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// isProtected reports whether the request is refused to set typeField at
+// path, because of its `bind:"-"` or `bind:"readonly"` tag or because the
+// AllowFields list of this call names neither path, a struct holding it nor
+// a field under it. Paths are matched ignoring case.
+func (o *bindOptions) isProtected(typeField reflect.StructField, path string) bool {
+	switch typeField.Tag.Get("bind") {
+	case "-", "readonly":
+		return true
+	}
+	if o.allowFields == nil || o.allowFields[path] {
+		return false
+	}
+	path = strings.ToLower(path)
+	for key := range o.allowFields {
+		key = strings.ToLower(key)
+		if key == path || isSubPath(path, key) || isSubPath(key, path) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSubPath reports whether path names a field nested under parent, e.g.
+// "profile.name" or "members[0]" under "members".
+func isSubPath(path, parent string) bool {
+	return strings.HasPrefix(path, parent+".") || strings.HasPrefix(path, parent+"[")
+}
+
+// protectedError reports a request trying to set a protected field, or nil
+// when RejectProtected is not set.
+func (o *bindOptions) protectedError(key string) error {
+	if !o.rejectProtected {
+		return nil
+	}
+	return errors.New(key + " can't be set by the request.")
+}
+
+// bindProtectedJSON unmarshals body into target, leaving the protected
+// fields of val, nested ones included, at their current value. Their keys are
+// removed from body first, spelled as sent like FieldMask paths.
+func (o *bindOptions) bindProtectedJSON(val reflect.Value, target interface{}, body []byte) error {
+	var removed []string
+	body, _, _ = rewriteJSON(val.Type(), "", body, "", func(typeField reflect.StructField, path string, _ json.RawMessage) (json.RawMessage, jsonAction, error) {
+		if o.isProtected(typeField, path) {
+			removed = append(removed, path)
+			return nil, jsonDrop, nil
+		}
+		return nil, jsonWalk, nil
+	})
+
+	for _, path := range removed {
+		if err := o.protectedError(path); err != nil {
+			return err
+		}
+		o.forget(path)
+	}
+	return unmarshalJSON(body, target)
+}
+
+// forget drops path and everything nested under it from the report and mask.
+func (o *bindOptions) forget(path string) {
+	for p := range o.mask {
+		if p == path || strings.HasPrefix(p, path+".") {
+			delete(o.mask, p)
+		}
+	}
+	if o.report != nil {
+		delete(o.report, path)
+	}
+}
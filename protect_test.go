@@ -0,0 +1,195 @@
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type protectUser struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	IsAdmin bool   `json:"is_admin" bind:"readonly"`
+	Balance int    `json:"balance" bind:"-"`
+}
+
+func ExampleRejectProtected() {
+	req, _ := http.NewRequest("GET", "http://localhost:9000?name=Arya&is_admin=1", nil)
+
+	var user protectUser
+	err := BindRequest(req, &user, RejectProtected())
+	fmt.Println(err)
+	// Output: is_admin can't be set by the request.
+}
+
+func TestBindRequestProtected(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Form = url.Values{"name": {"Arya"}, "email": {"arya@stark"}, "is_admin": {"1"}}
+	req.PostForm = url.Values{"balance": {"100"}}
+
+	user := protectUser{Balance: 5}
+	if err := BindRequest(req, &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.IsAdmin || user.Balance != 5 {
+		t.Error("protected form field set !", user)
+	}
+	if user.Name != "Arya" {
+		t.Error("unprotected form field not set !")
+	}
+
+	user = protectUser{}
+	if err := BindRequest(req, &user, AllowFields("name")); err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "Arya" || user.Email != "" {
+		t.Error("allowlist mismatch !", user)
+	}
+
+	err := BindRequest(req, &user, AllowFields("name"), RejectProtected())
+	if err == nil {
+		t.Error("protected field attempt not rejected !")
+	}
+}
+
+func TestBindRequestProtectedJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Header.Set("Content-Type", "application/json")
+	body := []byte(`{"name": "Arya", "is_admin": true, "balance": 100}`)
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	user := protectUser{Balance: 5}
+	mask := FieldMask{}
+	if err := BindRequest(req, &user, WithFieldMask(mask)); err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "Arya" || user.IsAdmin || user.Balance != 5 {
+		t.Error("protected json field set !", user)
+	}
+	if mask.Has("is_admin") || mask.Has("balance") || !mask.Has("name") {
+		t.Error("protected json field left in mask !", mask.Paths())
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	err := BindRequest(req, &protectUser{}, RejectProtected())
+	if err == nil {
+		t.Error("protected json field attempt not rejected !")
+	}
+}
+
+type protectProfile struct {
+	Nickname string `json:"nickname"`
+	IsAdmin  bool   `json:"is_admin,omitempty" bind:"-"`
+}
+
+type protectAudit struct {
+	CreatedBy string `json:"created_by" bind:"readonly"`
+}
+
+type protectGrant interface{ Scope() string }
+
+type protectRoleGrant struct {
+	Type  string `json:"type"`
+	Role  string `json:"role"`
+	Admin bool   `json:"admin" bind:"-"`
+}
+
+func (g protectRoleGrant) Scope() string { return g.Role }
+
+type protectAccount struct {
+	protectAudit
+	Name     string          `json:"name"`
+	Profile  protectProfile  `json:"profile"`
+	Backup   *protectProfile `json:"backup"`
+	Members  []protectProfile
+	Grants   []protectGrant `json:"grants" discriminator:"type"`
+	Settings map[string]protectProfile
+}
+
+func init() {
+	RegisterVariant(reflect.TypeOf((*protectGrant)(nil)).Elem(), "role", protectRoleGrant{})
+}
+
+func TestBindRequestProtectedNestedJSON(t *testing.T) {
+	body := []byte(`{
+		"name": "Arya",
+		"created_by": "mallory",
+		"profile": {"nickname": "No One", "is_admin": true},
+		"backup": {"IS_ADMIN": true},
+		"Members": [{"nickname": "Jon"}, {"is_admin": true}],
+		"grants": [{"type": "role", "role": "viewer", "admin": true}],
+		"Settings": {"home": {"Is_Admin": true}}
+	}`)
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest("POST", "", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	account := protectAccount{protectAudit: protectAudit{CreatedBy: "admin"}}
+	mask := FieldMask{}
+	if err := BindRequest(newRequest(), &account, WithFieldMask(mask)); err != nil {
+		t.Fatal(err)
+	}
+	if account.CreatedBy != "admin" || account.Profile.IsAdmin || account.Backup == nil || account.Backup.IsAdmin ||
+		account.Members[1].IsAdmin || account.Settings["home"].IsAdmin {
+		t.Error("nested protected json field set !", account)
+	}
+	if grant := account.Grants[0].(protectRoleGrant); grant.Admin || grant.Role != "viewer" {
+		t.Error("protected variant field set !", grant)
+	}
+	if account.Name != "Arya" || account.Profile.Nickname != "No One" || account.Members[0].Nickname != "Jon" {
+		t.Error("unprotected nested json field not set !", account)
+	}
+	if mask.Has("profile.is_admin") || mask.Has("backup.IS_ADMIN") || !mask.Has("profile.nickname") {
+		t.Error("nested protected field left in mask !", mask.Paths())
+	}
+
+	err := BindRequest(newRequest(), &protectAccount{}, RejectProtected())
+	if err == nil || err.Error() != "created_by can't be set by the request." {
+		t.Error("nested protected json field attempt not rejected !", err)
+	}
+
+	account = protectAccount{}
+	if err := BindRequest(newRequest(), &account, AllowFields("name", "profile.nickname", "Members")); err != nil {
+		t.Fatal(err)
+	}
+	if account.Name != "Arya" || account.Profile.Nickname != "No One" || account.Profile.IsAdmin ||
+		account.Backup != nil || len(account.Members) != 2 || account.Members[1].IsAdmin || account.Grants != nil {
+		t.Error("nested allowlist mismatch !", account)
+	}
+}
+
+func TestBindRequestProtectedJSONKeys(t *testing.T) {
+	var target struct {
+		Name    string         `json:"name"`
+		IsAdmin bool           `json:"is_admin,omitempty" bind:"-"`
+		Profile protectProfile `json:"profile"`
+	}
+	bind := func(body string, opts ...BindOption) error {
+		req, _ := http.NewRequest("POST", "", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		return BindRequest(req, &target, opts...)
+	}
+
+	mask := FieldMask{}
+	if err := bind(`{"name": "Arya", "is_admin": true}`, WithFieldMask(mask)); err != nil {
+		t.Fatal(err)
+	}
+	if target.IsAdmin || mask.Has("is_admin") {
+		t.Error("omitempty protected field set !", target.IsAdmin, mask.Paths())
+	}
+	if err := bind(`{"is_admin": true}`, RejectProtected()); err == nil || err.Error() != "is_admin can't be set by the request." {
+		t.Error("omitempty protected field attempt not rejected !", err)
+	}
+	if err := bind(`{"profile": {"is_admin": true}}`, RejectProtected()); err == nil || err.Error() != "profile.is_admin can't be set by the request." {
+		t.Error("nested protected field attempt not rejected !", err)
+	}
+	if target.Profile.IsAdmin {
+		t.Error("nested protected field set !")
+	}
+}
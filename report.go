@@ -0,0 +1,74 @@
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Sources a bound field value can come from.
+const (
+	SourceForm     = "form"     // request.Form, the query string merged with the post form
+	SourcePostForm = "postform" // request.PostForm
+	SourceJSON     = "json"     // application/json request body
+	SourceDefault  = "default"  // `default` tag
+)
+
+var methodSources = map[string]string{
+	stateGet:  SourceForm,
+	statePost: SourcePostForm,
+}
+
+// FieldSource describes where BindRequest took a field value from.
+type FieldSource struct {
+	Source  string
+	Raw     string
+	Default bool
+}
+
+// BindReport maps the path of every field set by BindRequest to its source.
+type BindReport map[string]FieldSource
+
+// String lists the report as "path=source(raw)" pairs sorted by path,
+// handy for debug logging.
+func (r BindReport) String() string {
+	paths := make([]string, 0, len(r))
+	for path := range r {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	entries := make([]string, len(paths))
+	for i, path := range paths {
+		entries[i] = path + "=" + r[path].Source + "(" + r[path].Raw + ")"
+	}
+	return strings.Join(entries, " ")
+}
+
+func (r BindReport) add(path string, source FieldSource) {
+	if r == nil {
+		return
+	}
+	r[path] = source
+}
+
+// addJSON records every field of val whose key appears in body.
+func (r BindReport) addJSON(val reflect.Value, body []byte) {
+	if r == nil || val.Kind() != reflect.Struct {
+		return
+	}
+	var raw map[string]json.RawMessage
+	if json.Unmarshal(body, &raw) != nil {
+		return
+	}
+	for i := 0; i < val.NumField(); i++ {
+		key, ok := jsonKey(val.Type().Field(i))
+		if !ok {
+			continue
+		}
+		if msg, ok := raw[key]; ok {
+			r.add(key, FieldSource{Source: SourceJSON, Raw: string(msg)})
+		}
+	}
+}
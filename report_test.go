@@ -0,0 +1,86 @@
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func ExampleWithReport() {
+	req, _ := http.NewRequest("GET", "http://localhost:9000?limit=20", nil)
+
+	var target struct {
+		Limit int    `json:"limit"`
+		Sort  string `json:"sort" default:"id"`
+	}
+	report := BindReport{}
+	err := BindRequest(req, &target, WithReport(report))
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(report)
+	// Output: limit=form(20) sort=default(id)
+}
+
+func TestBindReport(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Form = url.Values{"name": {" Arya "}}
+	req.PostForm = url.Values{"age": {"14"}}
+
+	var target struct {
+		Name  string `json:"name"`
+		Age   int    `json:"age"`
+		House string `json:"house" default:"Stark"`
+		Title string `json:"title"`
+	}
+	report := BindReport{}
+	if err := BindRequest(req, &target, WithReport(report)); err != nil {
+		t.Fatal(err)
+	}
+
+	if report["name"] != (FieldSource{Source: SourceForm, Raw: " Arya "}) {
+		t.Error("form source mismatch !", report["name"])
+	}
+	if report["age"] != (FieldSource{Source: SourcePostForm, Raw: "14"}) {
+		t.Error("post form source mismatch !", report["age"])
+	}
+	if report["house"] != (FieldSource{Source: SourceDefault, Raw: "Stark", Default: true}) {
+		t.Error("default source mismatch !", report["house"])
+	}
+	if _, ok := report["title"]; ok {
+		t.Error("unbound field reported !")
+	}
+}
+
+func TestBindReportJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"name": "Arya", "age": 14, "Title": "Lady"}`)))
+
+	var target struct {
+		Name  string `json:"name"`
+		House string `json:"house,omitempty" default:"Stark"`
+		Age   int    `json:"age,omitempty"`
+		Title string
+	}
+	report := BindReport{}
+	if err := BindRequest(req, &target, WithReport(report)); err != nil {
+		t.Fatal(err)
+	}
+
+	if report["name"] != (FieldSource{Source: SourceJSON, Raw: `"Arya"`}) {
+		t.Error("json source mismatch !", report["name"])
+	}
+	if report["age"] != (FieldSource{Source: SourceJSON, Raw: `14`}) {
+		t.Error("omitempty json source mismatch !", report["age"])
+	}
+	if report["Title"] != (FieldSource{Source: SourceJSON, Raw: `"Lady"`}) {
+		t.Error("untagged json source mismatch !", report["Title"])
+	}
+	if !report["house"].Default || target.House != "Stark" {
+		t.Error("json default mismatch !", report["house"])
+	}
+}
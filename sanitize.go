@@ -0,0 +1,210 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Modifier transforms a bound string value. param is the text after `=`
+// in the `mod` tag, e.g. "64" for `mod:"truncate=64"`.
+type Modifier func(value, param string) (string, error)
+
+var (
+	modifiersMu sync.RWMutex
+	modifiers   = map[string]Modifier{
+		"trim":            modTrim,
+		"lower":           modLower,
+		"upper":           modUpper,
+		"collapse_spaces": modCollapseSpaces,
+		"strip_tags":      modStripTags,
+		"truncate":        modTruncate,
+	}
+
+	spacesRegexp = regexp.MustCompile(`\s+`)
+	tagsRegexp   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// RegisterModifier makes fn available to `mod` tag under name, replacing any
+// modifier already registered with that name.
+func RegisterModifier(name string, fn Modifier) {
+	modifiersMu.Lock()
+	defer modifiersMu.Unlock()
+	modifiers[name] = fn
+}
+
+// Sanitize applies the `mod` tag of every string field in your struct,
+// recursing into nested structs, pointers, slices, Optional and Nullable.
+// A struct reached twice through pointers is sanitized once.
+// Fields without `mod` tag are left untouched.
+func Sanitize(target interface{}) error {
+	val := reflect.ValueOf(target)
+
+	if val.Kind() != reflect.Ptr {
+		return errors.New("Target can't be value")
+	}
+	return sanitize(val.Elem(), "", map[structRef]bool{})
+}
+
+// sanitize applies mods to val, or the `mod` tags of its fields. Pointers in
+// visited were sanitized already and are skipped, so cycles end.
+func sanitize(val reflect.Value, mods string, visited map[structRef]bool) error {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		if val.Kind() == reflect.Ptr {
+			ref := structRef{val.Pointer(), val.Type()}
+			if visited[ref] {
+				return nil
+			}
+			visited[ref] = true
+		}
+		return sanitize(val.Elem(), mods, visited)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if err := sanitize(val.Index(i), mods, visited); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if _, ok := presenceElem(val.Type()); ok {
+			if p := val.Interface().(presence); !p.isPresent() || p.isNull() {
+				return nil
+			}
+			return sanitize(val.FieldByName("Value"), mods, visited)
+		}
+		for i := 0; i < val.NumField(); i++ {
+			if val.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := sanitize(val.Field(i), val.Type().Field(i).Tag.Get("mod"), visited); err != nil {
+				return err
+			}
+		}
+	case reflect.String:
+		if mods == "" || mods == "-" || !val.CanSet() {
+			return nil
+		}
+		value, err := applyModifiers(mods, val.String())
+		if err != nil {
+			return err
+		}
+		val.SetString(value)
+	}
+	return nil
+}
+
+// sanitizeJSON applies the `mod` tag of the string fields, and slices of them,
+// present in data, a JSON document decoded into type t. Fields without `mod`
+// tag are left as sent.
+func sanitizeJSON(t reflect.Type, data []byte) ([]byte, error) {
+	data, _, err := rewriteJSON(t, "", data, "", func(typeField reflect.StructField, _ string, msg json.RawMessage) (json.RawMessage, jsonAction, error) {
+		mods := typeField.Tag.Get("mod")
+		if mods == "" || mods == "-" || !holdsStrings(typeField.Type) {
+			return nil, jsonWalk, nil
+		}
+
+		value := reflect.New(typeField.Type)
+		if json.Unmarshal(msg, value.Interface()) != nil {
+			return nil, jsonWalk, nil
+		}
+		if err := sanitize(value.Elem(), mods, map[structRef]bool{}); err != nil {
+			return nil, jsonWalk, err
+		}
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, jsonWalk, nil
+		}
+		return encoded, jsonReplace, nil
+	})
+	return data, err
+}
+
+// holdsStrings reports whether t is a string, or a pointer, slice, array,
+// Optional or Nullable of them.
+func holdsStrings(t reflect.Type) bool {
+	if elem, ok := presenceElem(t); ok {
+		return holdsStrings(elem)
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return holdsStrings(t.Elem())
+	}
+	return false
+}
+
+// modify prepares a raw bound value for typeField. Fields without `mod` tag
+// are trimmed and `mod:"-"` leaves the value untouched.
+func modify(typeField reflect.StructField, value string) (string, error) {
+	mods, ok := typeField.Tag.Lookup("mod")
+	if !ok {
+		return strings.TrimSpace(value), nil
+	}
+	if mods == "-" {
+		return value, nil
+	}
+	return applyModifiers(mods, value)
+}
+
+func applyModifiers(mods, value string) (string, error) {
+	modifiersMu.RLock()
+	defer modifiersMu.RUnlock()
+
+	for _, mod := range strings.Split(mods, ",") {
+		name, param := mod, ""
+		if i := strings.Index(mod, "="); i >= 0 {
+			name, param = mod[:i], mod[i+1:]
+		}
+		fn, ok := modifiers[strings.TrimSpace(name)]
+		if !ok {
+			return "", errors.New(name + " modifier is not registered.")
+		}
+
+		var err error
+		value, err = fn(value, param)
+		if err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+func modTrim(value, param string) (string, error) {
+	return strings.TrimSpace(value), nil
+}
+
+func modLower(value, param string) (string, error) {
+	return strings.ToLower(value), nil
+}
+
+func modUpper(value, param string) (string, error) {
+	return strings.ToUpper(value), nil
+}
+
+func modCollapseSpaces(value, param string) (string, error) {
+	return spacesRegexp.ReplaceAllString(value, " "), nil
+}
+
+func modStripTags(value, param string) (string, error) {
+	return tagsRegexp.ReplaceAllString(value, ""), nil
+}
+
+func modTruncate(value, param string) (string, error) {
+	n, err := strconv.Atoi(param)
+	if err != nil || n < 0 {
+		return "", errors.New("truncate modifier needs a length.")
+	}
+	if utf8.RuneCountInString(value) <= n {
+		return value, nil
+	}
+	return string([]rune(value)[:n]), nil
+}
@@ -0,0 +1,200 @@
+package structs
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func ExampleSanitize() {
+	comment := struct {
+		Author string `mod:"trim,lower"`
+		Body   string `mod:"strip_tags,collapse_spaces,truncate=16"`
+	}{
+		Author: "  Arya  ",
+		Body:   "<b>Valar</b>   morghulis,   valar dohaeris",
+	}
+	err := Sanitize(&comment)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%q %q\n", comment.Author, comment.Body)
+	// Output: "arya" "Valar morghulis,"
+}
+
+func TestSanitize(t *testing.T) {
+	type tag struct {
+		Name string `mod:"upper"`
+	}
+	target := struct {
+		Plain    string
+		Nickname string   `mod:"collapse_spaces,trim"`
+		Tags     []string `mod:"lower"`
+		Nested   []tag
+		Pointer  *tag
+	}{
+		Plain:    " untouched ",
+		Nickname: " the   hound ",
+		Tags:     []string{"GO", "Reflect"},
+		Nested:   []tag{{Name: "a"}},
+		Pointer:  &tag{Name: "b"},
+	}
+	if err := Sanitize(&target); err != nil {
+		t.Fatal(err)
+	}
+
+	if target.Plain != " untouched " {
+		t.Error("field without mod tag modified !")
+	}
+	if target.Nickname != "the hound" {
+		t.Error("chained modifiers mismatch !", target.Nickname)
+	}
+	if target.Tags[0] != "go" || target.Tags[1] != "reflect" {
+		t.Error("slice modifiers mismatch !")
+	}
+	if target.Nested[0].Name != "A" || target.Pointer.Name != "B" {
+		t.Error("nested modifiers mismatch !")
+	}
+}
+
+func TestSanitizeCustomModifier(t *testing.T) {
+	RegisterModifier("reverse", func(value, param string) (string, error) {
+		r := []rune(value)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r), nil
+	})
+
+	target := struct {
+		Name string `mod:"reverse"`
+	}{Name: "stark"}
+	if err := Sanitize(&target); err != nil || target.Name != "krats" {
+		t.Error("custom modifier mismatch !", err)
+	}
+
+	unknown := struct {
+		Name string `mod:"shout"`
+	}{Name: "stark"}
+	if err := Sanitize(&unknown); err == nil || err.Error() != "shout modifier is not registered." {
+		t.Error("unknown modifier not reported !", err)
+	}
+}
+
+func TestBindRequestModifiers(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+
+	var target struct {
+		Email    string `json:"email" mod:"trim,lower"`
+		Password string `json:"password" mod:"-"`
+		Name     string `json:"name"`
+	}
+	values := url.Values{}
+	values.Add("email", " Arya@Winterfell.North ")
+	values.Add("password", " secret ")
+	values.Add("name", " Arya ")
+	req.Form = values
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+
+	if target.Email != "arya@winterfell.north" {
+		t.Error("mod tag not applied !")
+	}
+	if target.Password != " secret " {
+		t.Error("mod:\"-\" still trims !")
+	}
+	if target.Name != strings.TrimSpace(target.Name) {
+		t.Error("default trim not applied !")
+	}
+}
+
+type sanitizeNode struct {
+	Name   string `mod:"trim,upper"`
+	Parent *sanitizeNode
+	Next   *sanitizeNode
+}
+
+func TestSanitizeCycle(t *testing.T) {
+	root := &sanitizeNode{Name: " root "}
+	child := &sanitizeNode{Name: " child ", Parent: root}
+	root.Next, child.Next = child, child
+
+	if err := Sanitize(root); err != nil {
+		t.Fatal(err)
+	}
+	if root.Name != "ROOT" || child.Name != "CHILD" {
+		t.Error("cyclic struct not sanitized !", root.Name, child.Name)
+	}
+}
+
+func TestBindRequestModifiersJSON(t *testing.T) {
+	body := `{"email": " A@B.C ", "name": " Arya ", "tags": [" Go ", "RUST"], "owner": {"email": " N@S.W "}, "contacts": [{"email": " J@S.W "}]}`
+	req, _ := http.NewRequest("POST", "", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	type contact struct {
+		Email string `json:"email" mod:"trim,lower"`
+	}
+	var target struct {
+		Email     string    `json:"email,omitempty" mod:"trim,lower"`
+		Name      string    `json:"name"`
+		Tags      []string  `json:"tags" mod:"trim,lower"`
+		Owner     *contact  `json:"owner"`
+		Contacts  []contact `json:"contacts"`
+		Untouched string    `json:"untouched" mod:"upper"`
+	}
+	target.Untouched = "keep"
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Email != "a@b.c" || target.Owner.Email != "n@s.w" || target.Contacts[0].Email != "j@s.w" {
+		t.Error("json modifiers not applied !", target.Email, target.Owner.Email, target.Contacts[0].Email)
+	}
+	if strings.Join(target.Tags, ",") != "go,rust" || target.Name != " Arya " || target.Untouched != "keep" {
+		t.Error("json modifiers mismatch !", target.Tags, target.Name, target.Untouched)
+	}
+
+	req, _ = http.NewRequest("POST", "", strings.NewReader(`{"email": "x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	var unknown struct {
+		Email string `json:"email" mod:"shout"`
+	}
+	if err := BindRequest(req, &unknown); err == nil {
+		t.Error("unknown json modifier not reported !")
+	}
+}
+
+func TestSanitizePresence(t *testing.T) {
+	type presenceForm struct {
+		Name     Optional[string]   `json:"name" mod:"trim,lower"`
+		Nick     Nullable[string]   `json:"nick" mod:"trim,lower"`
+		Tags     Optional[[]string] `json:"tags" mod:"trim"`
+		Missing  Optional[string]   `json:"missing" mod:"trim"`
+		Nothing  Nullable[string]   `json:"nothing" mod:"trim"`
+		Untagged Optional[string]   `json:"untagged"`
+	}
+
+	req, _ := http.NewRequest("POST", "", strings.NewReader(`{"name": "  ABC ", "nick": " Arry ", "tags": [" a "], "nothing": null, "untagged": " x "}`))
+	req.Header.Set("Content-Type", "application/json")
+	var target presenceForm
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Name != Some("abc") || target.Nick != NullableOf("arry") || target.Tags.Value[0] != "a" {
+		t.Error("json modifiers not applied to presence types !", target)
+	}
+	if target.Missing.Present || target.Nothing != Null[string]() || target.Untagged != Some(" x ") {
+		t.Error("json presence mismatch !", target)
+	}
+
+	target = presenceForm{Name: Some(" DEF "), Nick: NullableOf(" Sam ")}
+	if err := Sanitize(&target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Name != Some("def") || target.Nick != NullableOf("sam") || target.Missing.Present {
+		t.Error("presence types not sanitized !", target)
+	}
+}
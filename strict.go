@@ -0,0 +1,68 @@
+package structs
+
+import (
+	"net/url"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownParamsError is returned in strict mode when the request carries
+// query or form keys that match no field.
+type UnknownParamsError struct {
+	Keys []string
+}
+
+func (e *UnknownParamsError) Error() string {
+	return "Unknown parameters: " + strings.Join(e.Keys, ", ") + "."
+}
+
+// unknownParams returns the keys of valuesMap matching neither a field of
+// val nor a pattern of allow, sorted. Every key nested under a field with a
+// `discriminator` tag is accepted, as its variant is only known once bound.
+func unknownParams(val reflect.Value, valuesMap map[string]url.Values, allow []string, ignoreCase bool) []string {
+	var known, nested []string
+	for i := 0; i < val.NumField(); i++ {
+		typeField := val.Type().Field(i)
+		if typeField.Tag.Get("discriminator") != "" {
+			name, _ := jsonKey(typeField)
+			nested = append(nested, name+".")
+			continue
+		}
+		known = append(known, fieldKeys(typeField)...)
+	}
+
+	seen := map[string]bool{}
+	var unknown []string
+	for _, values := range valuesMap {
+		for key := range values {
+			if seen[key] || isKnownParam(key, known, nested, allow, ignoreCase) {
+				continue
+			}
+			seen[key] = true
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func isKnownParam(key string, known, nested, allow []string, ignoreCase bool) bool {
+	for _, k := range known {
+		if k == key || (ignoreCase && strings.EqualFold(k, key)) {
+			return true
+		}
+	}
+	for _, prefix := range nested {
+		if strings.HasPrefix(key, prefix) || (ignoreCase && len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix)) {
+			return true
+		}
+	}
+	for _, pattern := range allow {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package structs
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func ExampleStrict() {
+	req, _ := http.NewRequest("GET", "http://localhost:9000?limt=10&utm_source=mail", nil)
+
+	var target struct {
+		Limit int `json:"limit" default:"20"`
+	}
+	err := BindRequest(req, &target, Strict("utm_*"))
+	fmt.Println(err)
+	// Output: Unknown parameters: limt.
+}
+
+func TestBindRequestStrict(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Form = url.Values{"name": {"Arya"}, "uid": {"1"}, "utm_campaign": {"x"}, "Limit": {"5"}}
+	req.PostForm = url.Values{"nmae": {"Arya"}, "name": {"Arya"}}
+
+	var target struct {
+		Name   string `json:"name"`
+		UserID int    `json:"user_id" alias:"uid"`
+		Limit  int    `json:"limit"`
+	}
+	err := BindRequest(req, &target, Strict("utm_*"))
+	unknown, ok := err.(*UnknownParamsError)
+	if !ok || len(unknown.Keys) != 2 || unknown.Keys[0] != "Limit" || unknown.Keys[1] != "nmae" {
+		t.Error("unknown parameters mismatch !", err)
+	}
+
+	req.PostForm = url.Values{}
+	if err := BindRequest(req, &target, Strict("utm_*"), CaseInsensitive()); err != nil {
+		t.Error("case-insensitive key reported !", err)
+	}
+	if err := BindRequest(req, &target); err != nil {
+		t.Error("unknown parameters reported outside strict mode !", err)
+	}
+}
+
+func TestBindRequestStrictOmitEmpty(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+	req.Form = url.Values{"name": {"Arya"}, "nick": {"Arry"}, "Age": {"11"}}
+
+	var target struct {
+		Name string `json:"name,omitempty"`
+		Nick string `json:",omitempty" alias:"nick"`
+		Age  int
+	}
+	mask := FieldMask{}
+	if err := BindRequest(req, &target, Strict(), WithFieldMask(mask)); err != nil {
+		t.Fatal("json tag options reported !", err)
+	}
+	if target.Name != "Arya" || target.Nick != "Arry" || target.Age != 11 {
+		t.Error("json tag options binding mismatch !", target)
+	}
+	if !reflect.DeepEqual(mask.Paths(), []string{"Age", "Nick", "name"}) {
+		t.Error("json tag options mask mismatch !", mask.Paths())
+	}
+}
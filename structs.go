@@ -3,10 +3,12 @@ package structs
 import (
 	"encoding/json"
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -26,6 +28,11 @@ const (
 	stateString  = "string"
 )
 
+var (
+	errUnsupportedType = errors.New("type is not supported")
+	errInvalidValue    = errors.New("invalid value")
+)
+
 func getOppositeMethod(method string) string {
 	if method == stateGet {
 		return statePost
@@ -35,13 +42,35 @@ func getOppositeMethod(method string) string {
 
 // BindRequest will scan your struct and bind the request Values / Body
 // into your struct according to `json` tag on struct.
-func BindRequest(request *http.Request, target interface{}) error {
+// Fields missing from the request are filled from their `default` tag.
+// Int and float fields whose value is not a number are set to 0.
+// JSON bodies sent with gzip or deflate Content-Encoding are decoded first.
+// Fields tagged `bind:"-"` or `bind:"readonly"` are never set from the request.
+func BindRequest(request *http.Request, target interface{}, opts ...BindOption) error {
 	contentType := request.Header.Get("Content-Type")
+	options := newBindOptions(opts)
+
+	val := reflect.ValueOf(target)
+
+	if val.Kind() != reflect.Ptr {
+		return errors.New("Target can't be value")
+	}
+	val = val.Elem()
 
 	if request.Method == statePost && contentType == "application/json" {
-		body, _ := ioutil.ReadAll(request.Body)
-		json.Unmarshal(body, &target)
-		return nil
+		if err := setDefaults(val, "json", options.report); err != nil {
+			return err
+		}
+		body, err := readBody(request, options.maxBodySize)
+		if err != nil {
+			return err
+		}
+		options.report.addJSON(val, body)
+		options.mask.addJSON(body)
+		if body, err = sanitizeJSON(val.Type(), body); err != nil {
+			return err
+		}
+		return options.bindProtectedJSON(val, target, body)
 	}
 
 	request.ParseForm()
@@ -50,152 +79,290 @@ func BindRequest(request *http.Request, target interface{}) error {
 		statePost: request.PostForm,
 	}
 
-	val := reflect.ValueOf(target)
-
-	if val.Kind() != reflect.Ptr {
-		return errors.New("Target can't be value")
+	if options.strict {
+		if keys := unknownParams(val, valuesMap, options.allow, options.ignoreCase); len(keys) > 0 {
+			return &UnknownParamsError{Keys: keys}
+		}
 	}
-	val = val.Elem()
 
+	return bindForm(val, valuesMap, request.Method, "", options)
+}
+
+// bindForm binds the fields of val from valuesMap, reading every key with
+// prefix in front of it. Interface fields with a `discriminator` tag are
+// bound into the variant named by "<key>.<discriminator>".
+func bindForm(val reflect.Value, valuesMap map[string]url.Values, requestMethod, prefix string, options *bindOptions) error {
 	for i := 0; i < val.NumField(); i++ {
 		typeField := val.Type().Field(i)
-		tag := typeField.Tag.Get("json")
-		t := typeField.Type.String()
-		if len(valuesMap[request.Method][tag]) > 0 {
-			values := valuesMap[request.Method]
-			values[tag][0] = strings.TrimSpace(values[tag][0])
-			switch t {
-			case stateString:
-				val.Field(i).SetString(values[tag][0])
-			case stateInt64:
-				r, _ := strconv.ParseFloat(values[tag][0], 64)
-				val.Field(i).SetInt(int64(r))
-			case stateInt32:
-				r, _ := strconv.ParseFloat(values[tag][0], 64)
-				val.Field(i).SetInt(int64(r))
-			case stateInt16:
-				r, _ := strconv.ParseFloat(values[tag][0], 32)
-				val.Field(i).SetInt(int64(r))
-			case stateInt8:
-				r, _ := strconv.ParseFloat(values[tag][0], 32)
-				val.Field(i).SetInt(int64(r))
-			case stateInt:
-				r, _ := strconv.ParseFloat(values[tag][0], 32)
-				val.Field(i).SetInt(int64(r))
-			case stateFloat32:
-				res, _ := strconv.ParseFloat(values[tag][0], 32)
-				val.Field(i).SetFloat(res)
-			case stateFloat64:
-				res, _ := strconv.ParseFloat(values[tag][0], 64)
-				val.Field(i).SetFloat(res)
-			case stateBool:
-				b := false
-				if values[tag][0] == "1" || values[tag][0] == ok {
-					b = true
-				}
-				val.Field(i).SetBool(b)
-			default:
-				return errors.New(t + " type is not supported. You can skip this binding by changing json tag value to `-`")
+		name, _ := jsonKey(typeField)
+		path := prefix + name
+
+		if disc := typeField.Tag.Get("discriminator"); disc != "" && typeField.Type.Kind() == reflect.Interface {
+			if options.isProtected(typeField, path) {
+				continue
+			}
+			method, key, found := lookup(valuesMap, requestMethod, []string{path + "." + disc}, options.ignoreCase)
+			if !found {
+				continue
 			}
-		} else if len(valuesMap[getOppositeMethod(request.Method)][tag]) > 0 {
-			values := valuesMap[getOppositeMethod(request.Method)]
-			values[tag][0] = strings.TrimSpace(values[tag][0])
-			switch t {
-			case stateString:
-				val.Field(i).SetString(values[tag][0])
-			case stateInt64:
-				r, _ := strconv.ParseFloat(values[tag][0], 64)
-				val.Field(i).SetInt(int64(r))
-			case stateInt32:
-				r, _ := strconv.ParseFloat(values[tag][0], 64)
-				val.Field(i).SetInt(int64(r))
-			case stateInt16:
-				r, _ := strconv.ParseFloat(values[tag][0], 32)
-				val.Field(i).SetInt(int64(r))
-			case stateInt8:
-				r, _ := strconv.ParseFloat(values[tag][0], 32)
-				val.Field(i).SetInt(int64(r))
-			case stateInt:
-				r, _ := strconv.ParseFloat(values[tag][0], 32)
-				val.Field(i).SetInt(int64(r))
-			case stateFloat32:
-				res, _ := strconv.ParseFloat(values[tag][0], 32)
-				val.Field(i).SetFloat(res)
-			case stateFloat64:
-				res, _ := strconv.ParseFloat(values[tag][0], 64)
-				val.Field(i).SetFloat(res)
-			case stateBool:
-				b := false
-				if values[tag][0] == "1" || values[tag][0] == ok {
-					b = true
+			variant, err := newVariant(typeField.Type, path, valuesMap[method][key][0])
+			if err != nil {
+				return err
+			}
+			if err := bindForm(reflect.Indirect(variant), valuesMap, requestMethod, path+".", options); err != nil {
+				return err
+			}
+			options.mask.add(path)
+			val.Field(i).Set(variant)
+			continue
+		}
+
+		keys := fieldKeys(typeField)
+		for k := range keys {
+			keys[k] = prefix + keys[k]
+		}
+		method, key, found := lookup(valuesMap, requestMethod, keys, options.ignoreCase)
+		if options.isProtected(typeField, path) {
+			if found {
+				if err := options.protectedError(key); err != nil {
+					return err
 				}
-				val.Field(i).SetBool(b)
-			default:
-				return errors.New(t + " type is not supported. You can skip this binding by changing json tag value to `-`")
 			}
+			continue
 		}
+
+		var (
+			value  string
+			source FieldSource
+		)
+		if found {
+			options.mask.add(path)
+			raw := valuesMap[method][key][0]
+			source = FieldSource{Source: methodSources[method], Raw: raw}
+			modified, err := modify(typeField, raw)
+			if err != nil {
+				return err
+			}
+			value = modified
+		} else if def, ok := typeField.Tag.Lookup("default"); ok {
+			source = FieldSource{Source: SourceDefault, Raw: def, Default: true}
+			value = def
+		} else {
+			continue
+		}
+
+		if err := bindValue(val.Field(i), typeField, path, value, "json"); err != nil {
+			return err
+		}
+		options.report.add(path, source)
 	}
 	return nil
 }
 
-// ValidateStruct will validate struct if `required` tag is equal to true.
-func ValidateStruct(target interface{}) error {
-	val := reflect.ValueOf(target)
+// jsonKey returns the key of typeField in JSON documents: the name in its
+// `json` tag without options, or the field name. ok is false for fields JSON
+// skips, i.e. unexported ones and `json:"-"`.
+func jsonKey(typeField reflect.StructField) (key string, ok bool) {
+	key = strings.Split(typeField.Tag.Get("json"), ",")[0]
+	if key == "-" || typeField.PkgPath != "" {
+		return "", false
+	}
+	if key == "" {
+		key = typeField.Name
+	}
+	return key, true
+}
 
-	if val.Kind() != reflect.Ptr {
-		return errors.New("Target can't be value")
+// fieldKeys returns the request keys a field binds from: its JSON key
+// followed by the keys listed in its `alias` tag.
+func fieldKeys(typeField reflect.StructField) []string {
+	var keys []string
+	if key, ok := jsonKey(typeField); ok {
+		keys = append(keys, key)
+	}
+	if alias := typeField.Tag.Get("alias"); alias != "" {
+		for _, key := range strings.Split(alias, ",") {
+			keys = append(keys, strings.TrimSpace(key))
+		}
+	}
+	return keys
+}
+
+// lookup finds the first of keys holding a value, trying each key in the
+// method source before the opposite one. When ignoreCase is set and no key
+// matches exactly, keys are matched case-insensitively in the same order.
+func lookup(valuesMap map[string]url.Values, method string, keys []string, ignoreCase bool) (string, string, bool) {
+	methods := []string{method, getOppositeMethod(method)}
+	for _, key := range keys {
+		for _, m := range methods {
+			if len(valuesMap[m][key]) > 0 {
+				return m, key, true
+			}
+		}
+	}
+	if !ignoreCase {
+		return "", "", false
 	}
-	val = val.Elem()
 
+	for _, key := range keys {
+		for _, m := range methods {
+			candidates := make([]string, 0, len(valuesMap[m]))
+			for candidate := range valuesMap[m] {
+				candidates = append(candidates, candidate)
+			}
+			sort.Strings(candidates)
+			for _, candidate := range candidates {
+				if strings.EqualFold(candidate, key) && len(valuesMap[m][candidate]) > 0 {
+					return m, candidate, true
+				}
+			}
+		}
+	}
+	return "", "", false
+}
+
+// setDefaults fills every field carrying a `default` tag with its value,
+// recording them in report when it is not nil.
+func setDefaults(val reflect.Value, tagName string, report BindReport) error {
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
 	for i := 0; i < val.NumField(); i++ {
 		typeField := val.Type().Field(i)
-		tag := typeField.Tag.Get("json")
-		required := typeField.Tag.Get("required")
-		t := typeField.Type.String()
-		if required != "true" {
+		def, ok := typeField.Tag.Lookup("default")
+		if !ok {
 			continue
 		}
+		key := typeField.Tag.Get(tagName)
+		if tagName == "json" {
+			key, _ = jsonKey(typeField)
+		}
+		if err := bindValue(val.Field(i), typeField, key, def, tagName); err != nil {
+			return err
+		}
+		report.add(key, FieldSource{Source: SourceDefault, Raw: def, Default: true})
+	}
+	return nil
+}
 
-		switch t {
-		case stateString:
-			if val.Field(i).Interface().(string) == "" {
-				return errors.New(tag + " is required.")
-			}
-		case stateInt:
-			if val.Field(i).Interface().(int) == 0 {
-				return errors.New(tag + " is required.")
-			}
-		case stateInt8:
-			if val.Field(i).Interface().(int8) == 0 {
-				return errors.New(tag + " is required.")
-			}
-		case stateInt16:
-			if val.Field(i).Interface().(int16) == 0 {
-				return errors.New(tag + " is required.")
-			}
-		case stateInt32:
-			if val.Field(i).Interface().(int32) == 0 {
-				return errors.New(tag + " is required.")
-			}
-		case stateInt64:
-			if val.Field(i).Interface().(int64) == 0 {
-				return errors.New(tag + " is required.")
-			}
-		case stateFloat32:
-			if val.Field(i).Interface().(float32) == 0 {
-				return errors.New(tag + " is required.")
-			}
-		case stateFloat64:
-			if val.Field(i).Interface().(float64) == 0 {
-				return errors.New(tag + " is required.")
-			}
+// bindValue stores value into field. Slice fields with a `split` tag get
+// value split on the separator, each element converted by setField.
+// BindRequest, binding with the `json` tag, stores 0 into int and float
+// fields whose value is not a number, while the other sources reject it.
+func bindValue(field reflect.Value, typeField reflect.StructField, key, value, tagName string) error {
+	if setter, ok := asPresenceSetter(field); ok {
+		if _, nullable := setter.(interface{ IsNull() bool }); nullable && value == "" {
+			setter.setNull()
+			return nil
+		}
+		return bindValue(setter.set(), typeField, key, value, tagName)
+	}
+
+	enum, err := enumOf(typeField)
+	if err != nil {
+		return err
+	}
+
+	sep, ok := typeField.Tag.Lookup("split")
+	if !ok || field.Kind() != reflect.Slice {
+		err := convertValue(field, enum, value)
+		if err == errInvalidValue && tagName == "json" && enum == nil && isNumberKind(field.Kind()) {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
 		}
+		if err != nil {
+			return fieldError(err, key, typeField.Type.String(), tagName)
+		}
+		return nil
+	}
+
+	var parts []string
+	if value != "" {
+		parts = strings.Split(value, sep)
+	}
+	s := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := convertValue(s.Index(i), enum, strings.TrimSpace(part)); err != nil {
+			return fieldError(err, fmt.Sprintf("%s[%d]", key, i), field.Type().Elem().String(), tagName)
+		}
+	}
+	field.Set(s)
+	return nil
+}
 
+// isNumberKind reports whether kind is a built-in int or float kind.
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertValue stores value into field, by name when the field is an enum.
+func convertValue(field reflect.Value, enum *enumSet, value string) error {
+	if enum != nil {
+		return enum.set(field, value)
+	}
+	return setField(field, value)
+}
+
+// setField converts value according to the field type and stores it into field.
+// Empty values leave non string fields untouched.
+func setField(field reflect.Value, value string) error {
+	t := field.Type().String()
+	if value == "" && t != stateString {
+		return nil
+	}
+
+	switch t {
+	case stateString:
+		field.SetString(value)
+	case stateInt, stateInt8, stateInt16, stateInt32, stateInt64:
+		// Parse integers directly so large IDs keep every digit, falling
+		// back to float parsing for input like "12.5".
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			field.SetInt(n)
+			return nil
+		}
+		r, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errInvalidValue
+		}
+		field.SetInt(int64(r))
+	case stateFloat32:
+		res, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return errInvalidValue
+		}
+		field.SetFloat(res)
+	case stateFloat64:
+		res, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errInvalidValue
+		}
+		field.SetFloat(res)
+	case stateBool:
+		field.SetBool(value == "1" || value == ok)
+	case stateNumber, stateBigInt, stateBigFloat, stateBigRat:
+		return setBigNumber(field, value)
+	default:
+		return errUnsupportedType
 	}
 	return nil
 }
 
+// fieldError turns a setField error into a message naming the key and tag.
+func fieldError(err error, key, t, tagName string) error {
+	if err == errUnsupportedType {
+		return errors.New(t + " type is not supported. You can skip this binding by changing " + tagName + " tag value to `-`")
+	}
+	return errors.New(key + " is not a valid " + t + ".")
+}
+
 // ToMap returns map following the input struct.
+// Enum fields are converted back to their names and absent Optional or
+// Nullable fields are omitted.
 // Second params is used to conver map values into string.
 func ToMap(target interface{}, opts ...bool) map[string]interface{} {
 	var (
@@ -224,9 +391,31 @@ func ToMap(target interface{}, opts ...bool) map[string]interface{} {
 			key = v.Type().Field(i).Name
 		}
 
+		if enum, _ := enumOf(v.Type().Field(i)); enum != nil {
+			resolved, _ := resolve(vval)
+			if name, ok := enum.name(resolved); ok {
+				result[key] = name
+				continue
+			}
+			if names, ok := enum.sliceNames(resolved); ok {
+				result[key] = names
+				continue
+			}
+		}
+
 		value := vval.Interface()
+		if p, ok := value.(presence); ok {
+			if !p.isPresent() {
+				continue
+			}
+			value = p.get()
+			if p.isNull() {
+				value = nil
+			}
+		}
 		if value == nil {
-			value = nil
+			result[key] = nil
+			continue
 		}
 
 		if reflect.TypeOf(value).Kind() == reflect.Slice {
@@ -270,15 +459,34 @@ func toString(v interface{}) interface{} {
 	} else if reflect.TypeOf(v).Name() == stateInt64 {
 		return strconv.Itoa(int(v.(int64)))
 	} else if reflect.TypeOf(v).Name() == stateFloat32 {
-		return strconv.FormatFloat(float64(v.(float32)), 'f', 2, 32)
+		return formatFloat(float64(v.(float32)), 32)
 	} else if reflect.TypeOf(v).Name() == stateFloat64 {
-		return strconv.FormatFloat(v.(float64), 'f', 2, 64)
+		return formatFloat(v.(float64), 64)
 	} else if reflect.TypeOf(v).Name() == stateBool {
 		return v.(bool)
+	} else if s, ok := bigString(v); ok {
+		return s
 	}
 	return v
 }
 
+// formatFloat formats f with at least 2 decimals and as many more as it
+// takes to parse back to the same value.
+func formatFloat(f float64, bitSize int) string {
+	s := strconv.FormatFloat(f, 'f', -1, bitSize)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return s
+	}
+	i := strings.IndexByte(s, '.')
+	if i < 0 {
+		return s + ".00"
+	}
+	if decimals := len(s) - i - 1; decimals < 2 {
+		s += strings.Repeat("0", 2-decimals)
+	}
+	return s
+}
+
 func Copy(from interface{}, target interface{}) error {
 
 	if reflect.ValueOf(target).Kind() != reflect.Ptr {
@@ -289,6 +497,6 @@ func Copy(from interface{}, target interface{}) error {
 	if err != nil {
 		return err
 	}
-	json.Unmarshal(tmp, &target)
-	return nil
+	tmp = pruneAbsent(reflect.ValueOf(from), tmp)
+	return unmarshalJSON(tmp, target)
 }
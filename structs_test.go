@@ -247,6 +247,32 @@ func TestBindRequest(t *testing.T) {
 
 }
 
+func TestBindRequestDefault(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+
+	var target struct {
+		Limit int    `json:"limit" default:"10"`
+		Sort  string `json:"sort" default:"id"`
+	}
+	values := url.Values{}
+	values.Add("sort", "name")
+	req.Form = values
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Limit != 10 {
+		t.Error("default not applied !")
+	}
+	if target.Sort != "name" {
+		t.Error("default overrides request value !")
+	}
+
+	values.Set("limit", "ten")
+	if err := BindRequest(req, &target); err != nil || target.Limit != 0 {
+		t.Error("invalid number not bound as 0 !", err, target.Limit)
+	}
+}
+
 func ExampleValidateStruct() {
 
 	MyStruct := struct {
@@ -262,52 +288,64 @@ func ExampleValidateStruct() {
 func TestValidateStruct(t *testing.T) {
 
 	var target bindRequestStruct
-	err := ValidateStruct(&target)
+	err := ValidateStruct(&target, FailFast())
 	if err.Error() != "t_int is required." {
 		t.Error("t_int validation fail !")
 	}
 
 	target.TInt = 1
-	err = ValidateStruct(&target)
+	err = ValidateStruct(&target, FailFast())
 	if err.Error() != "t_int8 is required." {
 		t.Error("t_int8 validation fail !")
 	}
 
 	target.TInt8 = 1
-	err = ValidateStruct(&target)
+	err = ValidateStruct(&target, FailFast())
 	if err.Error() != "t_int16 is required." {
 		t.Error("t_int16 validation fail !")
 	}
 
 	target.TInt16 = 1
-	err = ValidateStruct(&target)
+	err = ValidateStruct(&target, FailFast())
 	if err.Error() != "t_int32 is required." {
 		t.Error("t_int32 validation fail !")
 	}
 
 	target.TInt32 = 1
-	err = ValidateStruct(&target)
+	err = ValidateStruct(&target, FailFast())
 	if err.Error() != "t_int64 is required." {
 		t.Error("t_int64 validation fail !")
 	}
 
 	target.TInt64 = 1
-	err = ValidateStruct(&target)
+	err = ValidateStruct(&target, FailFast())
 	if err.Error() != "t_float32 is required." {
 		t.Error("t_float32 validation fail !")
 	}
 
 	target.TFloat32 = 1
-	err = ValidateStruct(&target)
+	err = ValidateStruct(&target, FailFast())
 	if err.Error() != "t_float64 is required." {
 		t.Error("t_float64 validation fail !")
 	}
 
 	target.TFloat64 = 1
-	err = ValidateStruct(&target)
+	err = ValidateStruct(&target, FailFast())
+	if err.Error() != "t_bool is required." {
+		t.Error("t_bool validation fail !")
+	}
+
+	target.TBool = true
+	err = ValidateStruct(&target, FailFast())
 	if err.Error() != "t_string is required." {
 		t.Error("t_string validation fail !")
 	}
+
+	target.TString = "a"
+	err = ValidateStruct(&target, FailFast())
+	if err.Error() != "t_unsupported is required." {
+		t.Error("t_unsupported validation fail !")
+	}
 }
 
 func ExampleToMap() {
@@ -567,3 +605,101 @@ func TestCopySlice(t *testing.T) {
 		t.Error("Copy slice fail.")
 	}
 }
+
+func TestBindRequestSplit(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+
+	var target struct {
+		Fields []string  `json:"fields" split:","`
+		IDs    []int64   `json:"ids" split:"|"`
+		Scores []float64 `json:"scores" split:"," default:"1.5,2"`
+	}
+	values := url.Values{}
+	values.Add("fields", "id, name,email")
+	values.Add("ids", "1|2|3")
+	req.Form = values
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(target.Fields, []string{"id", "name", "email"}) {
+		t.Error("split string mismatch !", target.Fields)
+	}
+	if !reflect.DeepEqual(target.IDs, []int64{1, 2, 3}) {
+		t.Error("split int mismatch !", target.IDs)
+	}
+	if !reflect.DeepEqual(target.Scores, []float64{1.5, 2}) {
+		t.Error("split default mismatch !", target.Scores)
+	}
+
+	values.Set("ids", "1|two|3")
+	err := BindRequest(req, &target)
+	if err == nil || err.Error() != "ids[1] is not a valid int64." {
+		t.Error("split element error mismatch !", err)
+	}
+}
+
+func TestBindRequestAlias(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+
+	var target struct {
+		UserID int64  `json:"userId" alias:"user_id,uid"`
+		Name   string `json:"name"`
+	}
+	req.Form = url.Values{"uid": {"3"}, "user_id": {"2"}}
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.UserID != 2 {
+		t.Error("alias precedence mismatch !", target.UserID)
+	}
+
+	req.Form = url.Values{"userId": {"1"}, "user_id": {"2"}}
+	BindRequest(req, &target)
+	if target.UserID != 1 {
+		t.Error("json tag does not win over alias !", target.UserID)
+	}
+
+	target.UserID = 0
+	req.Form = url.Values{"UserID": {"4"}, "NAME": {"Arya"}}
+	BindRequest(req, &target)
+	if target.UserID != 0 || target.Name != "" {
+		t.Error("keys matched ignoring case by default !")
+	}
+	if err := BindRequest(req, &target, CaseInsensitive()); err != nil {
+		t.Fatal(err)
+	}
+	if target.UserID != 4 || target.Name != "Arya" {
+		t.Error("case-insensitive match mismatch !", target)
+	}
+
+	req.Form = url.Values{"USERID": {"5"}, "uid": {"6"}}
+	BindRequest(req, &target, CaseInsensitive())
+	if target.UserID != 6 {
+		t.Error("exact alias does not win over case-insensitive match !", target.UserID)
+	}
+}
+
+func TestBindRequestKeepsForm(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Form = url.Values{"email": {" Arya@Winterfell.North "}, "name": {" Arya "}}
+	req.PostForm = url.Values{"name": {" Arya "}}
+
+	var target struct {
+		Email string `json:"email" mod:"trim,lower"`
+		Name  string `json:"name"`
+	}
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Email != "arya@winterfell.north" || target.Name != "Arya" {
+		t.Error("values not modified in struct !", target)
+	}
+
+	if req.Form.Get("email") != " Arya@Winterfell.North " || req.Form.Get("name") != " Arya " {
+		t.Error("request.Form mutated !", req.Form)
+	}
+	if req.PostForm.Get("name") != " Arya " {
+		t.Error("request.PostForm mutated !", req.PostForm)
+	}
+}
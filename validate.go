@@ -0,0 +1,536 @@
+package structs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FieldContext describes the field a validation rule is checking.
+type FieldContext struct {
+	// Value holds the field, looking through Optional, Nullable, pointers and
+	// interfaces. Rules are skipped for nil and absent fields.
+	Value reflect.Value
+	// Param is the text after `=` in the rule, e.g. "3" for `min=3`.
+	Param string
+	// Parent is the struct holding the field.
+	Parent reflect.Value
+	// Path names the field in errors, e.g. "friends[2].name".
+	Path string
+
+	parents []reflect.Value
+}
+
+// Validator is implemented by structs checking invariants spanning several
+// fields, e.g. "at least one contact method". Validate shouldn't call
+// ValidateStruct on its own receiver.
+type Validator interface {
+	Validate() error
+}
+
+// rule checks a field against its param, returning false when the field
+// fails. Errors report a misconfigured tag, e.g. `min=abc`.
+type rule func(fl FieldContext) (bool, error)
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]rule{
+		"min":     ruleMin,
+		"max":     ruleMax,
+		"len":     ruleLen,
+		"regex":   ruleRegex,
+		"oneof":   ruleOneOf,
+		"eqfield": ruleEqField,
+		"nefield": ruleNeField,
+		"gtfield": ruleGtField,
+		"ltfield": ruleLtField,
+	}
+
+	regexpsMu sync.Mutex
+	regexps   = map[string]*regexp.Regexp{}
+)
+
+// RegisterRule makes fn available to `validate` tag under name, replacing any
+// rule already registered with that name. fn returns false when the field
+// fails. `required`, `required_*` and `dive` are handled by the validator
+// itself, so registering them panics.
+func RegisterRule(name string, fn func(fl FieldContext) bool) {
+	checkRuleName(name)
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	delete(ctxRules, name)
+	rules[name] = func(fl FieldContext) (bool, error) {
+		return fn(fl), nil
+	}
+}
+
+// checkRuleName panics when name is a rule the validator handles itself.
+func checkRuleName(name string) {
+	if _, ok := conditions[name]; ok || name == "required" || name == "dive" {
+		panic("structs: " + name + " validation rule can't be replaced")
+	}
+}
+
+// ValidateStruct will validate struct if `required` tag is equal to true.
+// Required fields fail when zero, e.g. false, a nil pointer, an empty slice or
+// map, or a zero time.Time, pointers failing when they point at a zero value
+// too unless PointerPresence is passed.
+// Enum fields holding a value outside their set are rejected.
+// Rules listed in `validate` tag, e.g. `validate:"required,min=3,max=64"`,
+// are checked as well. `min`, `max` and `len` bound numbers by value, exactly
+// for json.Number and math/big ones, and strings, slices and maps by length,
+// `regex` takes the rest of the tag so it must come last, and `oneof` lists
+// space separated values.
+// Every failing field is returned as ValidationErrors, unless FailFast is
+// passed. Misconfigured tags are reported as plain errors.
+// Nested structs, pointers to structs and the structs held by slices and maps
+// are validated too, with paths like "Friends[2].Name". `dive` applies the
+// rules after it to every element, e.g. `validate:"max=3,dive,min=1"`.
+// `eqfield`, `nefield`, `gtfield` and `ltfield` compare the field with another
+// one, and `required_if=Country DE`, `required_unless`, `required_with` and
+// `required_without` require it depending on others. Other fields are named by
+// path from the struct holding the field, e.g. "Address.Country", and every
+// leading "../" starts one struct further out.
+// Structs implementing Validator, nested ones included, have Validate called
+// once their fields are checked. ValidationErrors and FieldError it returns
+// are merged with their paths prefixed, other errors fail the struct itself.
+// Messages come from the "en" Bundle, or from the `msg` tag of the field,
+// e.g. `msg:"Please enter your {field}."`. ValidationErrors.Translate renders
+// them in another locale.
+func ValidateStruct(target interface{}, opts ...ValidateOption) error {
+	return ValidateStructCtx(context.Background(), target, opts...)
+}
+
+// validator collects the failures of a single ValidateStruct call.
+type validator struct {
+	options *validateOptions
+	errs    ValidationErrors
+	parents []reflect.Value
+	jobs    []*ctxJob
+	// visiting holds the structs being validated, so pointers back to them
+	// don't recurse forever.
+	visiting map[structRef]bool
+}
+
+// structRef identifies an addressable struct.
+type structRef struct {
+	addr uintptr
+	t    reflect.Type
+}
+
+// fail records a failing field, with msg from its `msg` tag.
+func (v *validator) fail(path, rule, param string, value reflect.Value, msg string) {
+	fe := &FieldError{Path: path, Rule: rule, Param: param, msg: msg}
+	if value.IsValid() && value.CanInterface() {
+		fe.Value = value.Interface()
+	}
+	fe.message = defaultMessage(fe)
+	v.errs = append(v.errs, fe)
+}
+
+// done reports whether validation should stop at the failures so far.
+func (v *validator) done() bool {
+	return v.options.failFast && len(v.errs) > 0
+}
+
+// validateStruct checks every field of val, naming them with prefix in front.
+// A struct already being validated further up, reached again through a
+// pointer, is skipped.
+func (v *validator) validateStruct(val reflect.Value, prefix string) error {
+	if val.CanAddr() {
+		ref := structRef{val.UnsafeAddr(), val.Type()}
+		if v.visiting[ref] {
+			return nil
+		}
+		if v.visiting == nil {
+			v.visiting = map[structRef]bool{}
+		}
+		v.visiting[ref] = true
+		defer delete(v.visiting, ref)
+	}
+	v.parents = append(v.parents, val)
+	defer func() { v.parents = v.parents[:len(v.parents)-1] }()
+
+	for i := 0; i < val.NumField() && !v.done(); i++ {
+		typeField := val.Type().Field(i)
+		if typeField.PkgPath != "" {
+			continue
+		}
+		name, ok := jsonKey(typeField)
+		if !ok {
+			name = typeField.Name
+		}
+		path := prefix + name
+		field := val.Field(i)
+		msg := typeField.Tag.Get("msg")
+
+		enum, err := enumOf(typeField)
+		if err != nil {
+			return err
+		}
+		if enum != nil && v.validateEnum(field, path, enum, msg) {
+			continue
+		}
+
+		if _, missing := resolve(field); typeField.Tag.Get("required") == "true" && v.isMissing(field, missing) {
+			v.fail(path, "required", "", field, msg)
+			continue
+		}
+		if err := v.validateField(field, path, parseRules(typeField.Tag.Get("validate")), msg); err != nil {
+			return err
+		}
+	}
+	if !v.done() {
+		v.validateHook(val, prefix)
+	}
+	return nil
+}
+
+// validateHook calls Validate on val when it implements Validator and merges
+// the errors it returns.
+func (v *validator) validateHook(val reflect.Value, prefix string) {
+	hook := val
+	if val.CanAddr() {
+		hook = val.Addr()
+	}
+	if !hook.CanInterface() {
+		return
+	}
+	validator, ok := hook.Interface().(Validator)
+	if !ok {
+		return
+	}
+
+	switch err := validator.Validate().(type) {
+	case nil:
+	case ValidationErrors:
+		for _, fe := range err {
+			v.merge(fe, prefix)
+		}
+	case *FieldError:
+		v.merge(err, prefix)
+	default:
+		fe := &FieldError{Path: strings.TrimSuffix(prefix, "."), Rule: "validate", message: err.Error(), raw: true}
+		if val.CanInterface() {
+			fe.Value = val.Interface()
+		}
+		v.errs = append(v.errs, fe)
+	}
+}
+
+// merge records fe, returned by Validate of the struct at prefix.
+func (v *validator) merge(fe *FieldError, prefix string) {
+	merged := *fe
+	merged.Path = prefix + fe.Path
+	if !fe.raw {
+		merged.message = defaultMessage(&merged)
+	} else if strings.HasPrefix(fe.message, fe.Path) {
+		merged.message = prefix + fe.message
+	}
+	v.errs = append(v.errs, &merged)
+}
+
+// validateEnum fails field, or every element of a slice field, holding a
+// value outside enum. Pointers and presence types are resolved first, and
+// zero or missing values are left to `required`.
+func (v *validator) validateEnum(field reflect.Value, path string, enum *enumSet, msg string) bool {
+	value, missing := resolve(field)
+	if missing {
+		return false
+	}
+	if value.Kind() != reflect.Slice {
+		if _, ok := enum.name(value); ok || value.IsZero() {
+			return false
+		}
+		v.fail(path, "enum", enum.list(), field, msg)
+		return true
+	}
+
+	failed := false
+	for i := 0; i < value.Len(); i++ {
+		elem := value.Index(i)
+		if _, ok := enum.name(elem); ok || elem.IsZero() {
+			continue
+		}
+		v.fail(fmt.Sprintf("%s[%d]", path, i), "enum", enum.list(), elem, msg)
+		failed = true
+	}
+	return failed
+}
+
+// validateField checks field against calls, then validates the structs it
+// holds. Rules after `dive` apply to every element of a slice or map field.
+func (v *validator) validateField(field reflect.Value, path string, calls []ruleCall, msg string) error {
+	own, elems, dive := calls, []ruleCall(nil), false
+	for i, r := range calls {
+		if r.name == "dive" {
+			own, elems, dive = calls[:i], calls[i+1:], true
+			break
+		}
+	}
+
+	failed := len(v.errs)
+	if err := v.validateRules(field, path, own, msg); err != nil || len(v.errs) > failed {
+		return err
+	}
+
+	value, _ := resolve(field)
+	if !value.IsValid() || (!dive && !holdsStructs(value.Type())) {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return v.validateStruct(value, path+".")
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len() && !v.done(); i++ {
+			if err := v.validateField(value.Index(i), fmt.Sprintf("%s[%d]", path, i), elems, msg); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			if v.done() {
+				break
+			}
+			if err := v.validateField(value.MapIndex(key), fmt.Sprintf("%s[%v]", path, key), elems, msg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// holdsStructs reports whether values of t may contain structs to validate.
+func holdsStructs(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return holdsStructs(t.Elem())
+	}
+	return false
+}
+
+// resolve returns the value field holds, looking through Optional, Nullable,
+// pointers and interfaces, and whether it is missing for `required`.
+// Optional and Nullable are missing when absent, big numbers when nil, slices
+// and maps when empty and everything else when zero.
+func resolve(field reflect.Value) (reflect.Value, bool) {
+	if !field.IsValid() {
+		return reflect.Value{}, true
+	}
+	if p, ok := field.Interface().(presence); ok {
+		if !p.isPresent() || p.isNull() {
+			return reflect.Value{}, !p.isPresent()
+		}
+		value, _ := resolve(reflect.ValueOf(p.get()))
+		return value, false
+	}
+
+	value := field
+	for (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && !value.IsNil() {
+		if value.Kind() == reflect.Ptr && isBigNumber(value.Type()) {
+			return value.Elem(), false
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return reflect.Value{}, true
+	case reflect.Slice, reflect.Map:
+		return value, value.Len() == 0
+	}
+	return value, value.IsZero()
+}
+
+// isMissing reports whether field, found missing by resolve, lacks a value
+// for `required`. With PointerPresence non-nil pointers are never missing.
+func (v *validator) isMissing(field reflect.Value, missing bool) bool {
+	if !missing || !v.options.pointerPresence {
+		return missing
+	}
+	for field.Kind() == reflect.Interface && !field.IsNil() {
+		field = field.Elem()
+	}
+	if field.Kind() == reflect.Ptr {
+		return field.IsNil()
+	}
+	return missing
+}
+
+// validateRules checks field against calls, stopping at the first one it
+// fails.
+func (v *validator) validateRules(field reflect.Value, key string, calls []ruleCall, msg string) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	value, missing := resolve(field)
+	missing = v.isMissing(field, missing)
+	fl := FieldContext{Value: value, Path: key, Parent: v.parents[len(v.parents)-1], parents: v.parents}
+	var pending []ctxCall
+	for _, r := range calls {
+		if cond, ok := conditions[r.name]; ok || r.name == "required" {
+			required := true
+			if ok {
+				var err error
+				if required, err = cond(r.param, v.parents); err != nil {
+					return errors.New(r.name + " rule of " + key + " " + err.Error())
+				}
+			}
+			if required && missing {
+				v.fail(key, r.name, r.param, field, msg)
+				return nil
+			}
+			continue
+		}
+
+		rulesMu.RLock()
+		fn, ok := rules[r.name]
+		ctxFn, isCtx := ctxRules[r.name]
+		rulesMu.RUnlock()
+		if !ok && !isCtx {
+			return errors.New(r.name + " validation rule is not registered.")
+		}
+		if !value.IsValid() {
+			continue
+		}
+		if isCtx {
+			pending = append(pending, ctxCall{ruleCall: r, fn: ctxFn})
+			continue
+		}
+
+		fl.Param = r.param
+		valid, err := fn(fl)
+		if err != nil {
+			return errors.New(r.name + " rule of " + key + " " + err.Error())
+		}
+		if !valid {
+			v.fail(key, r.name, r.param, field, msg)
+			return nil
+		}
+	}
+
+	if len(pending) > 0 {
+		fl.parents = append([]reflect.Value(nil), v.parents...)
+		v.jobs = append(v.jobs, &ctxJob{pos: len(v.errs), fl: fl, field: field, calls: pending, msg: msg})
+	}
+	return nil
+}
+
+type ruleCall struct {
+	name  string
+	param string
+}
+
+// parseRules splits a `validate` tag into rules. A regex rule takes the rest
+// of the tag, commas included.
+func parseRules(tag string) []ruleCall {
+	var calls []ruleCall
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "regex=") {
+			part, tag = tag, ""
+		} else if i := strings.Index(tag, ","); i >= 0 {
+			part, tag = tag[:i], tag[i+1:]
+		} else {
+			part, tag = tag, ""
+		}
+
+		name, param := part, ""
+		if i := strings.Index(part, "="); i >= 0 {
+			name, param = part[:i], part[i+1:]
+		}
+		calls = append(calls, ruleCall{name: strings.TrimSpace(name), param: param})
+	}
+	return calls
+}
+
+func ruleMin(fl FieldContext) (bool, error) {
+	n, err := compareSize(fl.Value, fl.Param)
+	return n >= 0, err
+}
+
+func ruleMax(fl FieldContext) (bool, error) {
+	n, err := compareSize(fl.Value, fl.Param)
+	return n <= 0, err
+}
+
+func ruleLen(fl FieldContext) (bool, error) {
+	if _, ok := numberRat(fl.Value); ok {
+		return false, errors.New("applies to strings, slices and maps only.")
+	}
+	n, err := compareSize(fl.Value, fl.Param)
+	return n == 0, err
+}
+
+// compareSize compares the value of a number field, or the length of a
+// string, slice or map field, with param. It returns -1, 0 or 1.
+func compareSize(field reflect.Value, param string) (int, error) {
+	n, ok := new(big.Rat).SetString(param)
+	if !ok {
+		return 0, errors.New("needs a number, got " + param + ".")
+	}
+	if value, ok := numberRat(field); ok {
+		return value.Cmp(n), nil
+	}
+	size, err := length(field)
+	if err != nil {
+		return 0, err
+	}
+	return big.NewRat(int64(size), 1).Cmp(n), nil
+}
+
+// length returns the length of a string, counted in runes, slice or map
+// field.
+func length(field reflect.Value) (int, error) {
+	switch field.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(field.String()), nil
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return field.Len(), nil
+	}
+	return 0, errors.New("doesn't apply to " + field.Type().String() + ".")
+}
+
+func ruleRegex(fl FieldContext) (bool, error) {
+	field, param := fl.Value, fl.Param
+	if field.Kind() != reflect.String {
+		return false, errors.New("applies to strings only.")
+	}
+
+	regexpsMu.Lock()
+	re, ok := regexps[param]
+	if !ok {
+		var err error
+		if re, err = regexp.Compile(param); err != nil {
+			regexpsMu.Unlock()
+			return false, errors.New("needs a valid expression, " + err.Error() + ".")
+		}
+		regexps[param] = re
+	}
+	regexpsMu.Unlock()
+
+	return re.MatchString(field.String()), nil
+}
+
+func ruleOneOf(fl FieldContext) (bool, error) {
+	value := fmt.Sprint(fl.Value.Interface())
+	for _, option := range strings.Fields(fl.Param) {
+		if value == option {
+			return true, nil
+		}
+	}
+	return false, nil
+}
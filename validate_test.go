@@ -0,0 +1,441 @@
+package structs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type validateRulesStruct struct {
+	Username string            `json:"username" validate:"required,min=3,max=8,regex=^[a-z]{1,8}$"`
+	Code     string            `json:"code" validate:"len=4"`
+	Age      int               `json:"age" validate:"min=18,max=130"`
+	Score    float64           `json:"score" validate:"max=1.5"`
+	Role     string            `json:"role" validate:"oneof=admin member guest"`
+	Tags     []string          `json:"tags" validate:"min=1,max=2"`
+	Labels   map[string]string `json:"labels" validate:"max=1"`
+	Nickname *string           `json:"nickname" validate:"min=2"`
+	Legacy   string            `json:"legacy" required:"true"`
+}
+
+func validRulesStruct() validateRulesStruct {
+	return validateRulesStruct{
+		Username: "arya",
+		Code:     "ABCD",
+		Age:      18,
+		Score:    1.5,
+		Role:     "member",
+		Tags:     []string{"stark"},
+		Legacy:   "ok",
+	}
+}
+
+func ExampleValidateStruct_rules() {
+	target := struct {
+		Username string `json:"username" validate:"required,min=3,max=64"`
+		Role     string `json:"role" validate:"oneof=admin member"`
+	}{Username: "al", Role: "admin"}
+
+	fmt.Println(ValidateStruct(&target))
+	// Output: username must have a length of at least 3.
+}
+
+func TestValidateStructRules(t *testing.T) {
+	short := "a"
+	cases := []struct {
+		update func(*validateRulesStruct)
+		err    string
+	}{
+		{func(v *validateRulesStruct) {}, ""},
+		{func(v *validateRulesStruct) { v.Username = "" }, "username is required."},
+		{func(v *validateRulesStruct) { v.Username = "ab" }, "username must have a length of at least 3."},
+		{func(v *validateRulesStruct) { v.Username = "abcdefghi" }, "username must have a length of at most 8."},
+		{func(v *validateRulesStruct) { v.Username = "Arya" }, "username must match ^[a-z]{1,8}$."},
+		{func(v *validateRulesStruct) { v.Code = "ABC" }, "code must have a length of 4."},
+		{func(v *validateRulesStruct) { v.Age = 17 }, "age must be at least 18."},
+		{func(v *validateRulesStruct) { v.Age = 131 }, "age must be at most 130."},
+		{func(v *validateRulesStruct) { v.Score = 1.6 }, "score must be at most 1.5."},
+		{func(v *validateRulesStruct) { v.Role = "owner" }, "role must be one of admin, member, guest."},
+		{func(v *validateRulesStruct) { v.Tags = nil }, "tags must have a length of at least 1."},
+		{func(v *validateRulesStruct) { v.Tags = []string{"a", "b", "c"} }, "tags must have a length of at most 2."},
+		{func(v *validateRulesStruct) { v.Labels = map[string]string{"a": "", "b": ""} }, "labels must have a length of at most 1."},
+		{func(v *validateRulesStruct) { v.Nickname = &short }, "nickname must have a length of at least 2."},
+		{func(v *validateRulesStruct) { v.Legacy = "" }, "legacy is required."},
+	}
+
+	for _, c := range cases {
+		target := validRulesStruct()
+		c.update(&target)
+		err := ValidateStruct(&target)
+		if (err == nil && c.err != "") || (err != nil && err.Error() != c.err) {
+			t.Errorf("expected %q, got %v", c.err, err)
+		}
+	}
+}
+
+func TestValidateStructRuleErrors(t *testing.T) {
+	unknown := struct {
+		Name string `validate:"required,shout"`
+	}{Name: "arya"}
+	if err := ValidateStruct(&unknown); err == nil || err.Error() != "shout validation rule is not registered." {
+		t.Error("unknown rule not reported !", err)
+	}
+
+	badParam := struct {
+		Age int `validate:"min=ten"`
+	}{Age: 1}
+	if err := ValidateStruct(&badParam); err == nil || err.Error() != "min rule of Age needs a number, got ten." {
+		t.Error("invalid param not reported !", err)
+	}
+
+	badKind := struct {
+		Age int `validate:"len=2"`
+	}{Age: 1}
+	if err := ValidateStruct(&badKind); err == nil {
+		t.Error("len on number not reported !")
+	}
+}
+
+type validateFriend struct {
+	Name string `validate:"required"`
+	Age  int    `validate:"min=1"`
+}
+
+type validateNested struct {
+	Owner   validateFriend
+	Partner *validateFriend
+	Friends []validateFriend
+	Family  map[string]*validateFriend
+	Tags    []string       `validate:"max=2,dive,min=2"`
+	Scores  map[string]int `validate:"dive,max=10"`
+	Matrix  [][]string     `validate:"dive,dive,required"`
+}
+
+func ExampleValidateStruct_nested() {
+	target := struct {
+		Friends []struct {
+			Name string `validate:"required"`
+		}
+	}{}
+	target.Friends = append(target.Friends, struct {
+		Name string `validate:"required"`
+	}{Name: "Arya"}, struct {
+		Name string `validate:"required"`
+	}{})
+
+	fmt.Println(ValidateStruct(&target))
+	// Output: Friends[1].Name is required.
+}
+
+func TestValidateStructNested(t *testing.T) {
+	target := validateNested{
+		Owner:   validateFriend{Name: "Ned", Age: 40},
+		Partner: &validateFriend{Age: 38},
+		Friends: []validateFriend{{Name: "Jon", Age: 14}, {Name: "Sam"}, {Age: 2}},
+		Family:  map[string]*validateFriend{"sister": {Name: "Sansa", Age: 13}, "brother": {Age: 10}, "ghost": nil},
+		Tags:    []string{"ok", "x"},
+		Scores:  map[string]int{"a": 3, "b": 11},
+		Matrix:  [][]string{{"a"}, {"b", ""}},
+	}
+
+	err := ValidateStruct(&target)
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatal("nested errors not collected !", err)
+	}
+
+	var paths []string
+	for _, fe := range errs {
+		paths = append(paths, fe.Path)
+	}
+	want := []string{
+		"Partner.Name",
+		"Friends[1].Age",
+		"Friends[2].Name",
+		"Family[brother].Name",
+		"Tags[1]",
+		"Scores[b]",
+		"Matrix[1][1]",
+	}
+	if fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Error("nested paths mismatch !", paths)
+	}
+	if errs[4].Error() != "Tags[1] must have a length of at least 2." {
+		t.Error("dive message mismatch !", errs[4])
+	}
+
+	target.Tags = []string{"a", "b", "c"}
+	err = ValidateStruct(&target, FailFast())
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 || errs[0].Path != "Partner.Name" {
+		t.Error("FailFast does not stop in nested structs !", err)
+	}
+}
+
+func ExampleRegisterRule() {
+	RegisterRule("sku", func(fl FieldContext) bool {
+		return strings.HasPrefix(fl.Value.String(), "SKU-")
+	})
+
+	item := struct {
+		Code string `json:"code" validate:"sku"`
+	}{Code: "ABC-1"}
+
+	fmt.Println(ValidateStruct(&item))
+	// Output: code failed the sku rule.
+}
+
+type ruleWarehouse struct {
+	Region string `json:"region"`
+	Code   string `json:"code" validate:"warehouse=3"`
+}
+
+func TestRegisterRule(t *testing.T) {
+	var got FieldContext
+	RegisterRule("warehouse", func(fl FieldContext) bool {
+		got = fl
+		return len(fl.Value.String()) == 3 && fl.Parent.FieldByName("Region").String() == "eu"
+	})
+
+	target := struct {
+		Warehouses []ruleWarehouse `json:"warehouses"`
+	}{Warehouses: []ruleWarehouse{{Region: "eu", Code: "AMS"}, {Region: "us", Code: "NYC"}}}
+
+	err := ValidateStruct(&target)
+	if err == nil || err.Error() != "warehouses[1].code failed the warehouse rule." {
+		t.Error("custom rule mismatch !", err)
+	}
+	if got.Path != "warehouses[1].code" || got.Param != "3" || got.Value.String() != "NYC" || got.Parent.Type() != reflect.TypeOf(ruleWarehouse{}) {
+		t.Error("field context mismatch !", got)
+	}
+
+	RegisterRule("min", func(fl FieldContext) bool { return true })
+	defer func() {
+		rulesMu.Lock()
+		rules["min"] = ruleMin
+		rulesMu.Unlock()
+	}()
+	short := struct {
+		Name string `validate:"min=3"`
+	}{Name: "a"}
+	if err := ValidateStruct(&short); err != nil {
+		t.Error("registered rule does not replace built in !", err)
+	}
+}
+
+func TestRegisterRuleReserved(t *testing.T) {
+	for _, name := range []string{"required", "required_if", "required_without", "dive"} {
+		func() {
+			defer func() {
+				if r := recover(); r != "structs: "+name+" validation rule can't be replaced" {
+					t.Error("reserved rule not rejected !", name, r)
+				}
+			}()
+			RegisterRule(name, func(fl FieldContext) bool { return true })
+		}()
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("reserved context rule not rejected !", name)
+				}
+			}()
+			RegisterRuleCtx(name, func(ctx context.Context, fl FieldContext) error { return nil })
+		}()
+	}
+
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	for _, name := range []string{"required", "required_if", "dive"} {
+		if _, ok := rules[name]; ok {
+			t.Error("reserved rule stored !", name)
+		}
+		if _, ok := ctxRules[name]; ok {
+			t.Error("reserved context rule stored !", name)
+		}
+	}
+}
+
+func TestRegisterRuleConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			RegisterRule(fmt.Sprintf("concurrent%d", i), func(fl FieldContext) bool { return true })
+		}(i)
+		go func() {
+			defer wg.Done()
+			target := struct {
+				Name string `validate:"min=1"`
+			}{Name: "a"}
+			if err := ValidateStruct(&target); err != nil {
+				t.Error("concurrent validation failed !", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type hookContact struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+func (c hookContact) Validate() error {
+	if c.Email == "" && c.Phone == "" {
+		return errors.New("A contact method is required.")
+	}
+	return nil
+}
+
+type hookPeriod struct {
+	From int `json:"from" validate:"min=1"`
+	To   int `json:"to"`
+}
+
+func (p *hookPeriod) Validate() error {
+	if p.To < p.From {
+		return ValidationErrors{{Path: "to", Rule: "gtefield", Param: "from", Value: p.To}}
+	}
+	return nil
+}
+
+type hookProfile struct {
+	Name     string        `json:"name" validate:"required"`
+	Contacts []hookContact `json:"contacts"`
+	Period   *hookPeriod   `json:"period"`
+}
+
+func (p *hookProfile) Validate() error {
+	if len(p.Contacts) == 0 {
+		return &FieldError{Path: "contacts", Rule: "required"}
+	}
+	return nil
+}
+
+func ExampleValidator() {
+	profile := hookProfile{Name: "Arya", Contacts: []hookContact{{Email: "arya@example.com"}, {}}}
+
+	fmt.Println(ValidateStruct(&profile))
+	// Output: A contact method is required.
+}
+
+func TestValidateStructHook(t *testing.T) {
+	profile := hookProfile{
+		Contacts: []hookContact{{Phone: "123"}, {}},
+		Period:   &hookPeriod{From: 0, To: -1},
+	}
+
+	err := ValidateStruct(&profile)
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatal("hook errors not merged !", err)
+	}
+
+	var got []string
+	for _, fe := range errs {
+		got = append(got, fe.Path+"|"+fe.Rule+"|"+fe.Error())
+	}
+	want := []string{
+		"name|required|name is required.",
+		"contacts[1]|validate|A contact method is required.",
+		"period.from|min|period.from must be at least 1.",
+		"period.to|gtefield|period.to failed the gtefield rule.",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Error("hook errors mismatch !", got)
+	}
+
+	if err := ValidateStruct(&hookProfile{Name: "Arya"}); err == nil || err.Error() != "contacts is required." {
+		t.Error("root hook mismatch !", err)
+	}
+
+	if err := ValidateStruct(&profile, FailFast()); err == nil || err.Error() != "name is required." {
+		t.Error("FailFast does not skip hooks !", err)
+	}
+}
+
+type requiredKinds struct {
+	Active   bool              `json:"active" required:"true"`
+	Address  *validateFriend   `json:"address" validate:"required"`
+	Count    *int              `json:"count" validate:"required"`
+	Tags     []string          `json:"tags" validate:"required"`
+	Labels   map[string]string `json:"labels" required:"true"`
+	Contact  interface{}       `json:"contact" validate:"required"`
+	Birthday time.Time         `json:"birthday" validate:"required"`
+	Balance  *big.Int          `json:"balance" validate:"required"`
+}
+
+func TestValidateStructRequiredKinds(t *testing.T) {
+	zero := 0
+	target := requiredKinds{Tags: []string{}, Labels: map[string]string{}, Count: &zero, Contact: (*int)(nil)}
+
+	err := ValidateStruct(&target)
+	expected := "active is required. address is required. count is required. tags is required. labels is required. " +
+		"contact is required. birthday is required. balance is required."
+	if err == nil || err.Error() != expected {
+		t.Error("required kinds mismatch !", err)
+	}
+
+	err = ValidateStruct(&target, PointerPresence())
+	expected = "active is required. address is required. tags is required. labels is required. " +
+		"contact is required. birthday is required. balance is required."
+	if err == nil || err.Error() != expected {
+		t.Error("PointerPresence mismatch !", err)
+	}
+
+	target = requiredKinds{
+		Active:   true,
+		Address:  &validateFriend{Name: "Winterfell", Age: 1},
+		Count:    &zero,
+		Tags:     []string{"a"},
+		Labels:   map[string]string{"a": "b"},
+		Contact:  &zero,
+		Birthday: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Balance:  new(big.Int),
+	}
+	if err := ValidateStruct(&target, PointerPresence()); err != nil {
+		t.Error("present fields reported !", err)
+	}
+	if err := ValidateStruct(&target); err == nil || err.Error() != "count is required. contact is required." {
+		t.Error("pointers to zero values accepted !", err)
+	}
+}
+
+type validateNode struct {
+	Name     string          `json:"name" validate:"required"`
+	Parent   *validateNode   `json:"parent"`
+	Children []*validateNode `json:"children"`
+}
+
+func TestValidateStructCycle(t *testing.T) {
+	root := &validateNode{Name: "root"}
+	shared := &validateNode{Parent: root}
+	root.Children = []*validateNode{{Name: "a", Parent: root}, shared, shared}
+	root.Parent = root
+
+	err := ValidateStruct(root)
+	if err == nil || err.Error() != "children[1].name is required. children[2].name is required." {
+		t.Error("cyclic struct validation mismatch !", err)
+	}
+}
+
+func TestValidateStructTagOptionPaths(t *testing.T) {
+	target := struct {
+		Name    string          `json:"name,omitempty" validate:"required"`
+		Secret  string          `json:"-" validate:"min=3"`
+		Friends []validateNode  `json:",omitempty"`
+		Owner   *validateFriend `json:"owner,omitempty"`
+	}{Secret: "ab", Friends: []validateNode{{Name: "a"}, {}}, Owner: &validateFriend{Age: 3}}
+
+	err := ValidateStruct(&target)
+	want := "name is required. Secret must have a length of at least 3. Friends[1].name is required. owner.Name is required."
+	if err == nil || err.Error() != want {
+		t.Error("json tag option paths mismatch !", err)
+	}
+}
@@ -0,0 +1,177 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	variantsMu sync.RWMutex
+	variants   = map[reflect.Type]map[string]reflect.Type{}
+)
+
+// RegisterVariant makes sample's type the concrete value of fields of
+// interface type iface whose discriminator equals name. Fields opt in with
+// a `discriminator` tag naming the key holding it, e.g. `discriminator:"type"`
+// reads {"type": "card", ...} into the variant registered as "card".
+// The variant must carry the discriminator key itself for Copy and
+// json.Marshal to keep it.
+func RegisterVariant(iface reflect.Type, name string, sample interface{}) {
+	t := reflect.TypeOf(sample)
+	if !t.Implements(iface) {
+		panic("structs: " + t.String() + " does not implement " + iface.String())
+	}
+
+	variantsMu.Lock()
+	defer variantsMu.Unlock()
+	if variants[iface] == nil {
+		variants[iface] = map[string]reflect.Type{}
+	}
+	variants[iface][name] = t
+}
+
+// newVariant returns a new value of the variant of iface registered as
+// name, addressable or as a pointer depending on how it was registered.
+func newVariant(iface reflect.Type, path, name string) (reflect.Value, error) {
+	variantsMu.RLock()
+	t, ok := variants[iface][name]
+	variantsMu.RUnlock()
+	if !ok {
+		return reflect.Value{}, errors.New(name + " is not a known variant of " + path + ".")
+	}
+
+	if t.Kind() == reflect.Ptr {
+		return reflect.New(t.Elem()), nil
+	}
+	return reflect.New(t).Elem(), nil
+}
+
+// unmarshalJSON decodes data into target like json.Unmarshal, then fills the
+// interface fields tagged `discriminator` with their registered variant,
+// parses *big.Float and *big.Rat fields at full precision and binds enum
+// fields from their names.
+// JSON errors are ignored as BindRequest and Copy always did, only unknown
+// variants are reported.
+func unmarshalJSON(data []byte, target interface{}) error {
+	json.Unmarshal(data, target)
+	return fillJSON(reflect.ValueOf(target), data, "")
+}
+
+func fillJSON(val reflect.Value, data []byte, path string) error {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		return fillJSON(val.Elem(), data, path)
+	case reflect.Slice:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		for i := 0; i < val.Len() && i < len(items); i++ {
+			if err := fillJSON(val.Index(i), items[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		var raw map[string]json.RawMessage
+		if json.Unmarshal(data, &raw) != nil {
+			return nil
+		}
+		for i := 0; i < val.NumField(); i++ {
+			typeField := val.Type().Field(i)
+			key, ok := jsonKey(typeField)
+			if !ok {
+				continue
+			}
+			msg, ok := raw[key]
+			if !ok {
+				continue
+			}
+
+			if isBigNumber(typeField.Type) {
+				setBigJSON(val.Field(i), msg)
+				continue
+			}
+			enum, err := enumOf(typeField)
+			if err != nil {
+				return err
+			}
+			if enum != nil {
+				if err := enum.setJSON(val.Field(i), msg, path+key); err != nil {
+					return err
+				}
+				continue
+			}
+			disc := typeField.Tag.Get("discriminator")
+			if disc == "" {
+				if err := fillJSON(val.Field(i), msg, path+key+"."); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := setVariant(val.Field(i), disc, msg, path+key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setVariant decodes msg into field, an interface or a slice of interfaces,
+// choosing the variant from the disc key of every object.
+func setVariant(field reflect.Value, disc string, msg json.RawMessage, path string) error {
+	switch field.Kind() {
+	case reflect.Interface:
+		v, err := decodeVariant(field.Type(), disc, msg, path)
+		if err != nil {
+			return err
+		}
+		field.Set(v)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.Interface {
+			return nil
+		}
+		var items []json.RawMessage
+		if json.Unmarshal(msg, &items) != nil || items == nil {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		s := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			v, err := decodeVariant(field.Type().Elem(), disc, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+			s.Index(i).Set(v)
+		}
+		field.Set(s)
+	}
+	return nil
+}
+
+func decodeVariant(iface reflect.Type, disc string, msg json.RawMessage, path string) (reflect.Value, error) {
+	var head map[string]json.RawMessage
+	if json.Unmarshal(msg, &head) != nil || head == nil {
+		return reflect.Zero(iface), nil
+	}
+	var name string
+	json.Unmarshal(head[disc], &name)
+
+	v, err := newVariant(iface, path, name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	target := v
+	if v.Kind() != reflect.Ptr {
+		target = v.Addr()
+	}
+	if err := unmarshalJSON(msg, target.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
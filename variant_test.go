@@ -0,0 +1,108 @@
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type paymentMethod interface {
+	Kind() string
+}
+
+type cardPayment struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+}
+
+func (c cardPayment) Kind() string { return c.Type }
+
+type bankPayment struct {
+	Type    string `json:"type"`
+	Account int64  `json:"account"`
+}
+
+func (b *bankPayment) Kind() string { return b.Type }
+
+type order struct {
+	ID       int             `json:"id"`
+	Payment  paymentMethod   `json:"payment" discriminator:"type"`
+	Fallback []paymentMethod `json:"fallback" discriminator:"type"`
+}
+
+func init() {
+	paymentType := reflect.TypeOf((*paymentMethod)(nil)).Elem()
+	RegisterVariant(paymentType, "card", cardPayment{})
+	RegisterVariant(paymentType, "bank", &bankPayment{})
+}
+
+func ExampleRegisterVariant() {
+	req, _ := http.NewRequest("POST", "", bytes.NewReader([]byte(`{"id": 1, "payment": {"type": "card", "number": "4111"}}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	var target order
+	err := BindRequest(req, &target)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Printf("%#v\n", target.Payment)
+	// Output: structs.cardPayment{Type:"card", Number:"4111"}
+}
+
+func TestBindRequestVariantJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", "", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{
+		"id": 7,
+		"payment": {"type": "bank", "account": 1234},
+		"fallback": [{"type": "card", "number": "4111"}, {"type": "bank", "account": 5}]
+	}`)))
+
+	var target order
+	if err := BindRequest(req, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.ID != 7 {
+		t.Error("plain field mismatch !")
+	}
+	if bank, ok := target.Payment.(*bankPayment); !ok || bank.Account != 1234 {
+		t.Error("variant mismatch !", target.Payment)
+	}
+	if len(target.Fallback) != 2 || target.Fallback[0].(cardPayment).Number != "4111" || target.Fallback[1].Kind() != "bank" {
+		t.Error("variant slice mismatch !", target.Fallback)
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"payment": {"type": "cash"}}`)))
+	err := BindRequest(req, &order{})
+	if err == nil || err.Error() != "cash is not a known variant of payment." {
+		t.Error("unknown variant not reported !", err)
+	}
+}
+
+func TestBindRequestVariantForm(t *testing.T) {
+	req, _ := http.NewRequest("GET", "", nil)
+	req.Form = url.Values{"id": {"3"}, "payment.type": {"bank"}, "payment.account": {"42"}}
+
+	var target order
+	if err := BindRequest(req, &target, Strict()); err != nil {
+		t.Fatal(err)
+	}
+	if bank, ok := target.Payment.(*bankPayment); !ok || bank.Type != "bank" || bank.Account != 42 {
+		t.Error("form variant mismatch !", target.Payment)
+	}
+}
+
+func TestCopyVariant(t *testing.T) {
+	from := order{ID: 1, Payment: cardPayment{Type: "card", Number: "4111"}}
+	var to order
+	if err := Copy(from, &to); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(from, to) {
+		t.Error("copy variant mismatch !", to)
+	}
+}